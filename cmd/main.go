@@ -1,12 +1,17 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"errors"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/jmoiron/sqlx"
@@ -14,13 +19,20 @@ import (
 	"github.com/kirinyoku/echo-wire-bot/internal/bot/middleware"
 	"github.com/kirinyoku/echo-wire-bot/internal/botkit"
 	"github.com/kirinyoku/echo-wire-bot/internal/config"
+	"github.com/kirinyoku/echo-wire-bot/internal/extractor"
 	"github.com/kirinyoku/echo-wire-bot/internal/fetcher"
+	"github.com/kirinyoku/echo-wire-bot/internal/hashtag"
 	"github.com/kirinyoku/echo-wire-bot/internal/notifier"
+	"github.com/kirinyoku/echo-wire-bot/internal/page"
 	"github.com/kirinyoku/echo-wire-bot/internal/storage"
 	"github.com/kirinyoku/echo-wire-bot/internal/summary"
+	"github.com/kirinyoku/echo-wire-bot/internal/telegraph"
 	_ "github.com/lib/pq"
 )
 
+// telegraphTimeout limits the time spent on a single request to the Telegraph API.
+const telegraphTimeout = 30 * time.Second
+
 func main() {
 	botAPI, err := tgbotapi.NewBotAPI(config.Get().TelegramBotToken)
 	if err != nil {
@@ -37,19 +49,204 @@ func main() {
 	defer db.Close()
 
 	var (
-		articleStorage = storage.NewArticleStorage(db)
-		sourceStorage  = storage.NewSourceStorage(db)
-		summarizer     = summary.NewOpenAISummarizer(config.Get().OpenAIKey, config.Get().OpenAIModel, config.Get().OpenAIPrompt)
-		fetcher        = fetcher.New(articleStorage, sourceStorage, config.Get().FetchInterval, config.Get().FilterKeywords)
-		notifier       = notifier.New(
+		articleStorage   = storage.NewArticleStorage(db)
+		sourceStorage    = storage.NewSourceStorage(db)
+		templateStorage  = storage.NewTemplateStorage(db)
+		contentStorage   = storage.NewContentStorage(db)
+		telegraphStorage = storage.NewTelegraphStorage(db)
+		summaryStorage   = storage.NewSummaryStorage(db)
+		usageStorage     = storage.NewUsageStorage(db)
+		promptStorage    = storage.NewPromptStorage(db)
+	)
+
+	notifierOpts := []notifier.Option{
+		notifier.WithRetry(config.Get().SendMaxAttempts, config.Get().SendRetryBackoff),
+	}
+
+	if config.Get().ModerationEnabled {
+		if config.Get().ModerationChatID == 0 {
+			log.Printf("moderation is enabled without a moderators chat, set EW_MODERATION_CHAT_ID")
+			return
+		}
+
+		notifierOpts = append(notifierOpts, notifier.WithModeration(config.Get().ModerationChatID, config.Get().ModerationAutoApprove))
+	}
+
+	var dryRunSink notifier.DryRunSink = notifier.LogSink{}
+
+	switch {
+	case config.Get().DryRunAdminChatID != 0:
+		dryRunSink = notifier.NewChatSink(botAPI, config.Get().DryRunAdminChatID)
+	case config.Get().DryRunLogFile != "":
+		dryRunSink = notifier.NewFileSink(config.Get().DryRunLogFile)
+	}
+
+	notifierOpts = append(notifierOpts, notifier.WithExpiryReport(config.Get().ExpiryReportChatID, config.Get().ExpiryReportInterval))
+	notifierOpts = append(notifierOpts, notifier.WithDryRun(config.Get().DryRun, config.Get().DryRunChatIDs, dryRunSink))
+
+	summaryChain, err := newSummaryChain()
+	if err != nil {
+		log.Printf("failed to initialize summarizer: %v", err)
+		return
+	}
+
+	// A nil chain must not be stored in the interface, which would not be nil then.
+	var summaryBackend summary.Backend
+	if summaryChain != nil {
+		summaryBackend = summaryChain
+	}
+
+	summarizer := summary.New(summaryBackend, config.Get().OpenAIPrompt)
+
+	if summaryChain != nil {
+		summarizer.SetRequestTimeout(summaryChain.Timeout())
+	}
+
+	prices, err := summary.ParsePrices(config.Get().SummaryPrices)
+	if err != nil {
+		log.Printf("failed to parse summary prices: %v", err)
+		return
+	}
+
+	summarizer.SetAccountant(summary.NewAccountant(
+		usageStorage,
+		prices,
+		config.Get().SummaryDailyBudget,
+		config.Get().SummaryMonthlyBudget,
+		config.Get().SummaryOverBudget == "skip",
+	))
+	summarizer.SetContextTokens(config.Get().SummaryContextTokens, config.Get().SummaryDefaultContextTokens)
+	summarizer.SetPostProcessing(config.Get().SummaryMaxLength, config.Get().SummaryLanguageCheck)
+
+	if config.Get().SummaryRequestsPerMinute > 0 || config.Get().SummaryTokensPerMinute > 0 {
+		summarizer.SetLimiter(summary.NewLimiter(config.Get().SummaryRequestsPerMinute, config.Get().SummaryTokensPerMinute))
+	}
+
+	if config.Get().SummaryFallback || config.Get().SummaryProvider == "extractive" {
+		summarizer.SetFallback(summary.NewExtractiveSummarizer(config.Get().SummarySentences))
+	}
+
+	var summaryModels []string
+	if summaryChain != nil {
+		summaryModels = summaryChain.Models()
+	}
+
+	log.Printf(
+		"%s summarizer is enabled: %v, models: %v, extractive fallback: %v",
+		config.Get().SummaryProvider,
+		summaryBackend != nil,
+		summaryModels,
+		config.Get().SummaryFallback || config.Get().SummaryProvider == "extractive",
+	)
+	summarizer.SetTranslatePrompt(config.Get().OpenAITranslatePrompt)
+
+	if config.Get().SummaryCacheEnabled {
+		notifierOpts = append(notifierOpts, notifier.WithSummaryCache(summaryStorage))
+	}
+
+	notifierOpts = append(notifierOpts, notifier.WithAlerts(config.Get().SummaryAlertChatID))
+	notifierOpts = append(notifierOpts, notifier.WithPrompts(promptStorage, config.Get().OpenAIPrompt, config.Get().SummaryMaxLength))
+	notifierOpts = append(notifierOpts, notifier.WithTranslation(summarizer, config.Get().PostLanguage, config.Get().ChatLanguages))
+
+	if config.Get().HashtagLimit > 0 {
+		var entities notifier.EntityExtractor
+		if config.Get().HashtagEntities {
+			entities = summarizer
+		}
+
+		notifierOpts = append(notifierOpts, notifier.WithHashtags(
+			hashtag.New(config.Get().HashtagLimit, config.Get().HashtagSynonyms),
+			entities,
+		))
+	}
+
+	if config.Get().TelegraphEnabled {
+		notifierOpts = append(notifierOpts, notifier.WithTelegraph(telegraph.NewPublisher(
+			telegraph.NewClient(config.Get().TelegraphBaseURL, &http.Client{Timeout: telegraphTimeout}),
+			telegraphStorage,
+			contentStorage,
+			config.Get().TelegraphAccessToken,
+			config.Get().TelegraphAuthorName,
+		)))
+	}
+
+	if config.Get().BreakingEnabled {
+		rules := notifier.BreakingRules{
+			Keywords:  config.Get().BreakingKeywords,
+			SourceIDs: config.Get().BreakingSourceIDs,
+			Threshold: config.Get().BreakingUrgency,
+		}
+
+		if config.Get().BreakingUrgency > 0 {
+			rules.Scorer = summarizer
+		}
+
+		notifierOpts = append(notifierOpts, notifier.WithBreaking(
+			rules,
+			config.Get().BreakingInterval,
+			config.Get().BreakingMaxAge,
+			config.Get().BreakingMaxPerHour,
+			config.Get().BreakingPin,
+			config.Get().BreakingSound,
+		))
+	}
+
+	notifierOpts = append(notifierOpts, notifier.WithSilentPosts(config.Get().SilentPosts))
+
+	if config.Get().EnrichmentEnabled {
+		notifierOpts = append(notifierOpts, notifier.WithEnrichment(
+			summarizer,
+			notifier.EnrichmentRules{
+				Topic:         config.Get().ChannelTopic,
+				MinRelevance:  config.Get().EnrichmentMinRelevance,
+				SkipClickbait: config.Get().EnrichmentSkipClickbait,
+			},
+			config.Get().EnrichmentInterval,
+			config.Get().EnrichmentMaxAttempts,
+			config.Get().EnrichmentRanking,
+		))
+	}
+
+	var (
+		fetcher = fetcher.New(
+			articleStorage,
+			sourceStorage,
+			config.Get().FetchInterval,
+			config.Get().FilterKeywords,
+			fetcher.WithDryRun(config.Get().DryRun),
+		)
+		pageFetcher = page.NewFetcher(
+			config.Get().PageTimeout,
+			config.Get().PageMaxBytes,
+			config.Get().PageUserAgent,
+			config.Get().PageRespectRobots,
+			config.Get().PageAllowPrivate,
+		)
+		extractor = extractor.New(
+			contentStorage,
+			pageFetcher,
+			config.Get().ExtractInterval,
+			config.Get().ExtractBatchSize,
+			config.Get().ExtractMaxAttempts,
+		)
+		newsNotifier = notifier.New(
 			articleStorage,
+			templateStorage,
+			contentStorage,
 			summarizer, botAPI,
 			config.Get().NotificationInterval,
-			config.Get().FetchInterval,
+			config.Get().ArticleExpiry,
 			config.Get().TelegramChannelID,
+			config.Get().PostTemplate,
+			config.Get().PostParseMode,
+			notifierOpts...,
 		)
 	)
 
+	if summaryChain != nil {
+		summaryChain.SetAlert(newsNotifier.AlertBreakerOpen)
+	}
+
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
@@ -58,6 +255,25 @@ func main() {
 	newsBot.RegisterCommand("deletesource", middleware.AdminsOnly(config.Get().TelegramChannelID, bot.ViewCmdDeleteSource(sourceStorage)))
 	newsBot.RegisterCommand("getsource", middleware.AdminsOnly(config.Get().TelegramChannelID, bot.ViewCmdGetSource(sourceStorage)))
 	newsBot.RegisterCommand("listsources", middleware.AdminsOnly(config.Get().TelegramChannelID, bot.ViewCmdListSources(sourceStorage)))
+	newsBot.RegisterCommand("settemplate", middleware.AdminsOnly(config.Get().TelegramChannelID, bot.ViewCmdSetTemplate(templateStorage, config.Get().PostParseMode)))
+	newsBot.RegisterCommand("deadletters", middleware.AdminsOnly(config.Get().TelegramChannelID, bot.ViewCmdDeadLetters(articleStorage)))
+	newsBot.RegisterCommand("retryarticle", middleware.AdminsOnly(config.Get().TelegramChannelID, bot.ViewCmdRetryArticle(articleStorage)))
+	newsBot.RegisterCommand("retract", middleware.AdminsOnly(config.Get().TelegramChannelID, bot.ViewCmdRetract(articleStorage, newsNotifier)))
+	newsBot.RegisterCommand("resummarize", middleware.AdminsOnly(config.Get().TelegramChannelID, bot.ViewCmdResummarize(articleStorage, newsNotifier)))
+	newsBot.RegisterCommand("editpost", middleware.AdminsOnly(config.Get().TelegramChannelID, bot.ViewCmdEditPost(articleStorage, newsNotifier)))
+	newsBot.RegisterCommand("modedit", middleware.AdminsOnly(config.Get().TelegramChannelID, bot.ViewCmdModEdit(newsNotifier)))
+	newsBot.RegisterCallback(notifier.ModerationCallbackPrefix, middleware.AdminsOnly(config.Get().TelegramChannelID, bot.ViewCallbackModeration(newsNotifier)))
+	newsBot.RegisterCommand("usage", middleware.AdminsOnly(
+		config.Get().TelegramChannelID,
+		bot.ViewCmdUsage(usageStorage, config.Get().SummaryDailyBudget, config.Get().SummaryMonthlyBudget),
+	))
+	newsBot.RegisterCommand("setprompt", middleware.AdminsOnly(config.Get().TelegramChannelID, bot.ViewCmdSetPrompt(promptStorage)))
+	newsBot.RegisterCommand("getprompt", middleware.AdminsOnly(config.Get().TelegramChannelID, bot.ViewCmdGetPrompt(promptStorage)))
+	newsBot.RegisterCommand("revertprompt", middleware.AdminsOnly(config.Get().TelegramChannelID, bot.ViewCmdRevertPrompt(promptStorage)))
+	newsBot.RegisterCommand("previewtemplate", middleware.AdminsOnly(
+		config.Get().TelegramChannelID,
+		bot.ViewCmdPreviewTemplate(articleStorage, templateStorage, newsNotifier, config.Get().PostTemplate, config.Get().PostParseMode),
+	))
 
 	go func(ctx context.Context) {
 		if err := fetcher.Run(ctx); err != nil {
@@ -71,7 +287,16 @@ func main() {
 	}(ctx)
 
 	go func(ctx context.Context) {
-		if err := notifier.Start(ctx); err != nil {
+		if err := extractor.Run(ctx); err != nil {
+			log.Printf("failed to run extractor: %v", err)
+			return
+		}
+
+		log.Printf("extractor stopped")
+	}(ctx)
+
+	go func(ctx context.Context) {
+		if err := newsNotifier.Start(ctx); err != nil {
 			if !errors.Is(err, context.Canceled) {
 				log.Printf("failed to run notifier: %v", err)
 				return
@@ -86,3 +311,101 @@ func main() {
 		return
 	}
 }
+
+// newSummaryChain creates the chain of language model backends: the backend of the configured summary
+// provider followed by the backups. It returns nil if no backend is configured, e.g. the provider is
+// the offline extractive summarizer, which leaves summaries to the extractive fallback.
+func newSummaryChain() (*summary.Chain, error) {
+	var links []summary.Link
+
+	primary, err := newSummaryBackend(config.Get().SummaryProvider, "", config.Get().SummaryTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if primary != nil {
+		links = append(links, summary.Link{Backend: primary, Timeout: config.Get().SummaryTimeout})
+	}
+
+	for _, backup := range config.Get().SummaryBackups {
+		link, err := newSummaryBackup(backup)
+		if err != nil {
+			return nil, err
+		}
+
+		links = append(links, link)
+	}
+
+	if len(links) == 0 {
+		return nil, nil
+	}
+
+	return summary.NewChain(links, config.Get().SummaryBreakerFailures, config.Get().SummaryBreakerCooldown), nil
+}
+
+// newSummaryBackup creates the backup backend described as "provider:model", optionally followed by
+// "@timeout", e.g. "ollama:llama3.1:8b@2m". Backups without a timeout use the timeout of the primary backend.
+func newSummaryBackup(backup string) (summary.Link, error) {
+	provider, model, ok := strings.Cut(strings.TrimSpace(backup), ":")
+	if !ok || model == "" {
+		return summary.Link{}, fmt.Errorf("invalid summary backup %q, expected provider:model", backup)
+	}
+
+	link := summary.Link{Timeout: config.Get().SummaryTimeout}
+
+	model, timeout, ok := strings.Cut(model, "@")
+	if ok {
+		d, err := time.ParseDuration(timeout)
+		if err != nil {
+			return summary.Link{}, fmt.Errorf("invalid timeout of summary backup %q: %w", backup, err)
+		}
+
+		link.Timeout = d
+	}
+
+	backend, err := newSummaryBackend(provider, model, link.Timeout)
+	if err != nil {
+		return summary.Link{}, err
+	}
+
+	if backend == nil {
+		return summary.Link{}, fmt.Errorf("summary backup %q is not configured", backup)
+	}
+
+	link.Backend = backend
+
+	return link, nil
+}
+
+// newSummaryBackend creates the backend of the provider with the model, or with the model configured
+// for the provider if the model is empty. The HTTP requests of the backend are limited to the timeout,
+// 0 for no limit. It returns nil if the provider is not configured.
+func newSummaryBackend(provider, model string, timeout time.Duration) (summary.Backend, error) {
+	httpClient := &http.Client{Timeout: timeout}
+
+	switch provider {
+	case "openai":
+		if config.Get().OpenAIKey == "" && config.Get().OpenAIBaseURL == "" {
+			return nil, nil
+		}
+
+		return summary.NewOpenAIBackend(config.Get().OpenAIKey, config.Get().OpenAIBaseURL, cmp.Or(model, config.Get().OpenAIModel)), nil
+	case "ollama":
+		return summary.NewOllamaBackend(config.Get().OllamaBaseURL, cmp.Or(model, config.Get().OllamaModel), httpClient), nil
+	case "anthropic":
+		if config.Get().AnthropicKey == "" {
+			return nil, nil
+		}
+
+		return summary.NewAnthropicBackend(
+			config.Get().AnthropicKey,
+			config.Get().AnthropicBaseURL,
+			cmp.Or(model, config.Get().AnthropicModel),
+			httpClient,
+		), nil
+	case "extractive", "":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown summary provider %q", provider)
+	}
+}
@@ -10,6 +10,8 @@ import (
 // AdminsOnly is a middleware function that restricts access to a handler
 // such that only administrators of a specified Telegram channel can execute it.
 // It takes the channel ID and the next handler as parameters and returns a wrapped handler.
+// Callback queries of other users are answered with a notification instead of a message,
+// so that their buttons stop loading without cluttering the chat.
 func AdminsOnly(channelID int64, next botkit.ViewFunc) botkit.ViewFunc {
 	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
 		admins, err := bot.GetChatAdministrators(
@@ -30,6 +32,11 @@ func AdminsOnly(channelID int64, next botkit.ViewFunc) botkit.ViewFunc {
 			}
 		}
 
+		if update.CallbackQuery != nil {
+			_, err := bot.Request(tgbotapi.NewCallback(update.CallbackQuery.ID, "You are not allowed to do this."))
+			return err
+		}
+
 		if _, err := bot.Send(tgbotapi.NewMessage(
 			update.FromChat().ID,
 			"You do not have permission to execute this command.",
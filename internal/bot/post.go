@@ -0,0 +1,62 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+)
+
+// PostedArticleProvider is an interface for retrieving posted articles from persistent storage,
+// either by their ID or by the channel message they were posted as.
+type PostedArticleProvider interface {
+	ArticleByID(ctx context.Context, id int64) (models.Article, error)
+	ArticleByMessage(ctx context.Context, chatID int64, messageID int) (models.Article, error)
+}
+
+// PostEditor is an interface for acting on the channel posts of articles.
+type PostEditor interface {
+	Retract(ctx context.Context, article models.Article) error
+	Resummarize(ctx context.Context, article models.Article) error
+	EditPost(ctx context.Context, article models.Article, summary string) error
+}
+
+// postedArticle finds the article a post command refers to. When the command replies to a channel post
+// forwarded to the bot, the article of that post is used and all command arguments are returned as the rest.
+// Otherwise the arguments must start with the article ID, and the text after it, on the same line
+// or on the following lines, is the rest.
+func postedArticle(ctx context.Context, provider PostedArticleProvider, msg *tgbotapi.Message) (models.Article, string, error) {
+	args := msg.CommandArguments()
+
+	if reply := msg.ReplyToMessage; reply != nil && reply.ForwardFromChat != nil && reply.ForwardFromMessageID != 0 {
+		article, err := provider.ArticleByMessage(ctx, reply.ForwardFromChat.ID, reply.ForwardFromMessageID)
+		if err != nil {
+			return models.Article{}, "", fmt.Errorf("no article found for the forwarded post: %w", err)
+		}
+
+		return article, strings.TrimSpace(args), nil
+	}
+
+	args = strings.TrimSpace(args)
+
+	idStr, rest := args, ""
+	if i := strings.IndexFunc(args, unicode.IsSpace); i >= 0 {
+		idStr, rest = args[:i], args[i:]
+	}
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return models.Article{}, "", fmt.Errorf("invalid article ID: %w", err)
+	}
+
+	article, err := provider.ArticleByID(ctx, id)
+	if err != nil {
+		return models.Article{}, "", err
+	}
+
+	return article, strings.TrimSpace(rest), nil
+}
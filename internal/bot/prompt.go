@@ -0,0 +1,55 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+)
+
+// promptHistoryLimit is the number of versions of a prompt listed by /getprompt.
+const promptHistoryLimit = 10
+
+// PromptStorage is an interface for managing versioned prompt templates in persistent storage.
+type PromptStorage interface {
+	PromptVersion(ctx context.Context, scope models.PromptScope, scopeID int64, version int) (models.Prompt, error)
+	Prompts(ctx context.Context, scope models.PromptScope, scopeID int64, limit uint64) ([]models.Prompt, error)
+	StorePrompt(ctx context.Context, scope models.PromptScope, scopeID int64, body string) (int, error)
+}
+
+// parsePromptScope parses the scope of a prompt command: "global", "source <source id>" or "chat <chat id>".
+// It returns the remaining arguments.
+func parsePromptScope(args []string) (models.PromptScope, int64, []string, error) {
+	if len(args) == 0 {
+		return "", 0, nil, fmt.Errorf("prompt scope is missing, use global, source <id> or chat <id>")
+	}
+
+	switch scope := models.PromptScope(strings.ToLower(args[0])); scope {
+	case models.PromptScopeGlobal:
+		return scope, 0, args[1:], nil
+	case models.PromptScopeSource, models.PromptScopeChat:
+		if len(args) < 2 {
+			return "", 0, nil, fmt.Errorf("%s ID is missing", scope)
+		}
+
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return "", 0, nil, fmt.Errorf("invalid %s ID: %w", scope, err)
+		}
+
+		return scope, id, args[2:], nil
+	default:
+		return "", 0, nil, fmt.Errorf("unknown prompt scope %q, use global, source <id> or chat <id>", args[0])
+	}
+}
+
+// promptScopeName returns the human-readable name of the scope of a prompt, e.g. "source 3".
+func promptScopeName(scope models.PromptScope, scopeID int64) string {
+	if scope == models.PromptScopeGlobal {
+		return string(scope)
+	}
+
+	return fmt.Sprintf("%s %d", scope, scopeID)
+}
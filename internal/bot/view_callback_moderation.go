@@ -0,0 +1,104 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/echo-wire-bot/internal/botkit"
+	"github.com/kirinyoku/echo-wire-bot/internal/notifier"
+)
+
+// Moderator is an interface for making decisions on articles waiting for moderation.
+type Moderator interface {
+	Approve(ctx context.Context, articleID int64) error
+	Reject(ctx context.Context, articleID int64) error
+	EditModerated(ctx context.Context, articleID int64, summary string) error
+}
+
+// ViewCallbackModeration creates a handler for the Approve, Reject and Edit summary buttons
+// attached to articles sent to moderators.
+func ViewCallbackModeration(moderator Moderator) botkit.ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		query := update.CallbackQuery
+
+		action, idStr, _ := strings.Cut(botkit.CallbackPayload(query.Data), ":")
+
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			return err
+		}
+
+		var answer string
+
+		switch action {
+		case notifier.ModerationActionApprove:
+			if err := moderator.Approve(ctx, id); err != nil {
+				return answerCallback(bot, query, fmt.Sprintf("Failed to approve: %v", err), err)
+			}
+
+			answer = "Approved"
+		case notifier.ModerationActionReject:
+			if err := moderator.Reject(ctx, id); err != nil {
+				return answerCallback(bot, query, fmt.Sprintf("Failed to reject: %v", err), err)
+			}
+
+			answer = "Rejected"
+		case notifier.ModerationActionEdit:
+			msg := tgbotapi.NewMessage(
+				query.Message.Chat.ID,
+				fmt.Sprintf("Send /modedit %d with the new summary on the following lines.", id),
+			)
+			msg.ReplyToMessageID = query.Message.MessageID
+
+			if _, err := bot.Send(msg); err != nil {
+				return err
+			}
+
+			answer = "Waiting for the new summary"
+		default:
+			return fmt.Errorf("unknown moderation action %q", action)
+		}
+
+		return answerCallback(bot, query, answer, nil)
+	}
+}
+
+// ViewCmdModEdit creates a bot command handler for replacing the summary of an article waiting for moderation.
+// The first line of the command arguments is the article ID, the following lines are the new summary.
+func ViewCmdModEdit(moderator Moderator) botkit.ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		idStr, text, _ := strings.Cut(strings.TrimSpace(update.Message.CommandArguments()), "\n")
+
+		id, err := strconv.ParseInt(strings.TrimSpace(idStr), 10, 64)
+		if err != nil {
+			return err
+		}
+
+		if text = strings.TrimSpace(text); text == "" {
+			return fmt.Errorf("new summary is empty")
+		}
+
+		if err := moderator.EditModerated(ctx, id, text); err != nil {
+			return err
+		}
+
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "The summary has been successfully updated")
+		if _, err := bot.Send(msg); err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
+
+// answerCallback answers the callback query with a notification and returns the given error.
+func answerCallback(bot *tgbotapi.BotAPI, query *tgbotapi.CallbackQuery, text string, err error) error {
+	if _, answerErr := bot.Request(tgbotapi.NewCallback(query.ID, text)); answerErr != nil && err == nil {
+		return answerErr
+	}
+
+	return err
+}
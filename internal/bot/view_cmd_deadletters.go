@@ -0,0 +1,67 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/echo-wire-bot/internal/botkit"
+	"github.com/kirinyoku/echo-wire-bot/internal/botkit/markup"
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+)
+
+// deadLettersLimit is the maximum number of dead-lettered articles listed at once.
+const deadLettersLimit = 20
+
+// DeadArticleLister is an interface for retrieving dead-lettered articles from persistent storage.
+type DeadArticleLister interface {
+	AllDead(ctx context.Context, limit uint64) ([]models.Article, error)
+}
+
+// ViewCmdDeadLetters creates a bot command handler for listing articles that failed to be posted.
+// For every article it shows the number of attempts and the last error.
+func ViewCmdDeadLetters(lister DeadArticleLister) botkit.ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		articles, err := lister.AllDead(ctx, deadLettersLimit)
+		if err != nil {
+			return err
+		}
+
+		msgText := "There are no dead\\-lettered articles"
+
+		if len(articles) > 0 {
+			articleInfos := make([]string, 0, len(articles))
+
+			for _, article := range articles {
+				articleInfos = append(articleInfos, formatDeadArticle(article))
+			}
+
+			msgText = fmt.Sprintf(
+				"Dead\\-lettered articles \\(showing up to %d\\):\n\n%s\n\nUse /retryarticle with the article ID to post it again\\.",
+				deadLettersLimit,
+				strings.Join(articleInfos, "\n\n"),
+			)
+		}
+
+		reply := tgbotapi.NewMessage(update.Message.Chat.ID, msgText)
+		reply.ParseMode = parseModeMarkdownV2
+
+		if _, err := bot.Send(reply); err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
+
+// formatDeadArticle formats the details of a dead-lettered article into a Markdown-compatible string.
+func formatDeadArticle(article models.Article) string {
+	return fmt.Sprintf(
+		"*%s*\nID: `%d`\nAttempts: %d\nLast error: %s",
+		markup.EscapeForMarkdown(article.Title),
+		article.ID,
+		article.SendAttempts,
+		markup.EscapeForMarkdown(article.LastError),
+	)
+}
@@ -0,0 +1,40 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/echo-wire-bot/internal/botkit"
+)
+
+// ViewCmdEditPost creates a bot command handler for replacing the summary of a posted article.
+// The article is given by its ID or by replying to the post forwarded to the bot.
+// The text after the ID becomes the new summary of the channel message; the rest of the post,
+// such as the title, the link and the hashtags, is rendered with the post template as before.
+func ViewCmdEditPost(provider PostedArticleProvider, editor PostEditor) botkit.ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		article, text, err := postedArticle(ctx, provider, update.Message)
+		if err != nil {
+			return err
+		}
+
+		if text == "" {
+			return fmt.Errorf("new post text is empty")
+		}
+
+		// The summary is written by an admin rather than generated with a prompt.
+		article.PromptVersion = ""
+
+		if err := editor.EditPost(ctx, article, text); err != nil {
+			return err
+		}
+
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "The summary of the post has been successfully replaced")
+		if _, err := bot.Send(msg); err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
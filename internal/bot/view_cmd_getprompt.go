@@ -0,0 +1,60 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/echo-wire-bot/internal/botkit"
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+)
+
+// ViewCmdGetPrompt creates a bot command handler for showing a prompt template and its version history.
+// The arguments are the scope of the prompt: global (default), source <source id> or chat <chat id>.
+func ViewCmdGetPrompt(storage PromptStorage) botkit.ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		args := strings.Fields(update.Message.CommandArguments())
+		if len(args) == 0 {
+			args = []string{string(models.PromptScopeGlobal)}
+		}
+
+		scope, scopeID, _, err := parsePromptScope(args)
+		if err != nil {
+			return err
+		}
+
+		prompts, err := storage.Prompts(ctx, scope, scopeID, promptHistoryLimit)
+		if err != nil {
+			return err
+		}
+
+		var msgText string
+
+		if len(prompts) == 0 {
+			msgText = fmt.Sprintf("There is no %s prompt", promptScopeName(scope, scopeID))
+		} else {
+			msgText = formatPrompts(promptScopeName(scope, scopeID), prompts)
+		}
+
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, msgText)
+		if _, err := bot.Send(msg); err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
+
+// formatPrompts formats the latest version of a prompt followed by its version history, newest first.
+func formatPrompts(name string, prompts []models.Prompt) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "The %s prompt, version %d:\n\n%s\n\nHistory:", name, prompts[0].Version, prompts[0].Body)
+
+	for _, prompt := range prompts {
+		fmt.Fprintf(&b, "\n• v%d, %s", prompt.Version, prompt.CreatedAt.Format("02.01.2006 15:04"))
+	}
+
+	return b.String()
+}
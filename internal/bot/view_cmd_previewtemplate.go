@@ -0,0 +1,102 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/echo-wire-bot/internal/botkit"
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+	"github.com/kirinyoku/echo-wire-bot/internal/render"
+)
+
+// ArticleProvider is an interface for retrieving an article from persistent storage.
+type ArticleProvider interface {
+	ArticleByID(ctx context.Context, id int64) (models.Article, error)
+}
+
+// PostDataProvider is an interface for building the template fields of an article as it is posted,
+// with the summary, the translation and the hashtags of the post.
+type PostDataProvider interface {
+	PostData(ctx context.Context, article models.Article) render.Data
+}
+
+// previewTimeout limits the time spent on preparing the article to preview.
+// It replaces the short deadline of update handlers, as the summary of an article that
+// has not been posted yet may have to be generated.
+const previewTimeout = 2 * time.Minute
+
+// TemplateProvider is an interface for retrieving post templates from persistent storage.
+type TemplateProvider interface {
+	Template(ctx context.Context, name string) (models.PostTemplate, error)
+}
+
+// ViewCmdPreviewTemplate creates a bot command handler for previewing a post template.
+// The first line of the command arguments is the ID of the article to render. The following
+// lines, if any, are the template to preview; otherwise the template in effect is used.
+// The article is rendered with the same fields as its post.
+func ViewCmdPreviewTemplate(
+	articles ArticleProvider,
+	templates TemplateProvider,
+	posts PostDataProvider,
+	defaultTemplate string,
+	parseMode string,
+) botkit.ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		idStr, text, _ := strings.Cut(update.Message.CommandArguments(), "\n")
+
+		id, err := strconv.ParseInt(strings.TrimSpace(idStr), 10, 64)
+		if err != nil {
+			return err
+		}
+
+		article, err := articles.ArticleByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if strings.TrimSpace(text) == "" {
+			stored, err := templates.Template(ctx, render.PostTemplateName)
+			if err != nil {
+				return err
+			}
+
+			text = render.TemplateText(stored, defaultTemplate, parseMode)
+		}
+
+		prepareCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), previewTimeout)
+		defer cancel()
+
+		rendered, err := renderPreview(text, parseMode, posts.PostData(prepareCtx, article))
+		if err != nil {
+			msg := tgbotapi.NewMessage(update.Message.Chat.ID, fmt.Sprintf("Failed to render template: %v", err))
+			if _, err := bot.Send(msg); err != nil {
+				return err
+			}
+
+			return nil
+		}
+
+		reply := tgbotapi.NewMessage(update.Message.Chat.ID, rendered)
+		reply.ParseMode = parseMode
+
+		if _, err := bot.Send(reply); err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
+
+// renderPreview renders the template text with the fields of the post.
+func renderPreview(text, parseMode string, data render.Data) (string, error) {
+	tmpl, err := render.New(text, parseMode)
+	if err != nil {
+		return "", err
+	}
+
+	return tmpl.ExecuteLimited(data, render.MaxMessageLength)
+}
@@ -0,0 +1,39 @@
+package bot
+
+import (
+	"context"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/echo-wire-bot/internal/botkit"
+)
+
+// resummarizeTimeout limits the time spent on regenerating a summary.
+// It replaces the short deadline of update handlers, as summarization takes longer.
+const resummarizeTimeout = 2 * time.Minute
+
+// ViewCmdResummarize creates a bot command handler for regenerating the summary of a posted article.
+// The article is given by its ID or by replying to the post forwarded to the bot,
+// and its channel message is edited in place.
+func ViewCmdResummarize(provider PostedArticleProvider, editor PostEditor) botkit.ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		article, _, err := postedArticle(ctx, provider, update.Message)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), resummarizeTimeout)
+		defer cancel()
+
+		if err := editor.Resummarize(ctx, article); err != nil {
+			return err
+		}
+
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "The post has been successfully resummarized")
+		if _, err := bot.Send(msg); err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
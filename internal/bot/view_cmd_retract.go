@@ -0,0 +1,30 @@
+package bot
+
+import (
+	"context"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/echo-wire-bot/internal/botkit"
+)
+
+// ViewCmdRetract creates a bot command handler for deleting the channel post of an article.
+// The article is given by its ID or by replying to the post forwarded to the bot.
+func ViewCmdRetract(provider PostedArticleProvider, editor PostEditor) botkit.ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		article, _, err := postedArticle(ctx, provider, update.Message)
+		if err != nil {
+			return err
+		}
+
+		if err := editor.Retract(ctx, article); err != nil {
+			return err
+		}
+
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "The post has been successfully retracted")
+		if _, err := bot.Send(msg); err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,37 @@
+package bot
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/echo-wire-bot/internal/botkit"
+)
+
+// ArticleRequeuer is an interface for moving dead-lettered articles back to the posting queue.
+type ArticleRequeuer interface {
+	Requeue(ctx context.Context, id int64) error
+}
+
+// ViewCmdRetryArticle creates a bot command handler for retrying a dead-lettered article.
+// It parses the article ID from the command arguments and puts the article back in the queue.
+func ViewCmdRetryArticle(requeuer ArticleRequeuer) botkit.ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		id, err := strconv.ParseInt(strings.TrimSpace(update.Message.CommandArguments()), 10, 64)
+		if err != nil {
+			return err
+		}
+
+		if err := requeuer.Requeue(ctx, id); err != nil {
+			return err
+		}
+
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "The article has been put back in the queue")
+		if _, err := bot.Send(msg); err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,59 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/echo-wire-bot/internal/botkit"
+)
+
+// ViewCmdRevertPrompt creates a bot command handler for reverting a prompt template to an earlier version.
+// The arguments are the scope of the prompt followed by the version, e.g. "source 3 2".
+// The earlier version is stored as a new version, so that the history is kept.
+func ViewCmdRevertPrompt(storage PromptStorage) botkit.ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		scope, scopeID, rest, err := parsePromptScope(strings.Fields(update.Message.CommandArguments()))
+		if err != nil {
+			return err
+		}
+
+		if len(rest) != 1 {
+			return fmt.Errorf("prompt version is missing")
+		}
+
+		version, err := strconv.Atoi(strings.TrimPrefix(rest[0], "v"))
+		if err != nil {
+			return fmt.Errorf("invalid prompt version: %w", err)
+		}
+
+		prompt, err := storage.PromptVersion(ctx, scope, scopeID, version)
+		if err != nil {
+			return err
+		}
+
+		if prompt.Body == "" {
+			return fmt.Errorf("version %d of the %s prompt not found", version, promptScopeName(scope, scopeID))
+		}
+
+		stored, err := storage.StorePrompt(ctx, scope, scopeID, prompt.Body)
+		if err != nil {
+			return err
+		}
+
+		msg := tgbotapi.NewMessage(
+			update.Message.Chat.ID,
+			fmt.Sprintf(
+				"The %s prompt has been reverted to version %d as version %d",
+				promptScopeName(scope, scopeID), version, stored,
+			),
+		)
+		if _, err := bot.Send(msg); err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,45 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/echo-wire-bot/internal/botkit"
+)
+
+// ViewCmdSetPrompt creates a bot command handler for storing a new version of a prompt template.
+// The first line of the arguments is the scope of the prompt: global, source <source id> or chat <chat id>,
+// and the following lines are the prompt. The variables {source}, {language} and {length} of the prompt
+// are replaced with the name of the source, the language of the article and the maximum length of a summary.
+func ViewCmdSetPrompt(storage PromptStorage) botkit.ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		scopeLine, body, _ := strings.Cut(strings.TrimSpace(update.Message.CommandArguments()), "\n")
+
+		scope, scopeID, _, err := parsePromptScope(strings.Fields(scopeLine))
+		if err != nil {
+			return err
+		}
+
+		body = strings.TrimSpace(body)
+		if body == "" {
+			return fmt.Errorf("prompt text is empty")
+		}
+
+		version, err := storage.StorePrompt(ctx, scope, scopeID, body)
+		if err != nil {
+			return err
+		}
+
+		msg := tgbotapi.NewMessage(
+			update.Message.Chat.ID,
+			fmt.Sprintf("The %s prompt has been successfully updated to version %d", promptScopeName(scope, scopeID), version),
+		)
+		if _, err := bot.Send(msg); err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
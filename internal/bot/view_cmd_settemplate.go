@@ -0,0 +1,52 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/echo-wire-bot/internal/botkit"
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+	"github.com/kirinyoku/echo-wire-bot/internal/render"
+)
+
+// TemplateSetter is an interface for saving post templates to persistent storage.
+type TemplateSetter interface {
+	SetTemplate(ctx context.Context, template models.PostTemplate) error
+}
+
+// ViewCmdSetTemplate creates a bot command handler for replacing the channel post template.
+// The command arguments are used as the template text. The template is validated
+// against the parse mode before it is saved.
+func ViewCmdSetTemplate(setter TemplateSetter, parseMode string) botkit.ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		text := strings.TrimSpace(update.Message.CommandArguments())
+		if text == "" {
+			return fmt.Errorf("template text is empty")
+		}
+
+		if _, err := render.New(text, parseMode); err != nil {
+			msg := tgbotapi.NewMessage(update.Message.Chat.ID, fmt.Sprintf("Invalid template: %v", err))
+			if _, err := bot.Send(msg); err != nil {
+				return err
+			}
+
+			return nil
+		}
+
+		if err := setter.SetTemplate(ctx, models.PostTemplate{
+			Name: render.PostTemplateName,
+			Body: text,
+		}); err != nil {
+			return err
+		}
+
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "The post template has been successfully updated")
+		if _, err := bot.Send(msg); err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,97 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/echo-wire-bot/internal/botkit"
+	"github.com/kirinyoku/echo-wire-bot/internal/botkit/markup"
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+)
+
+// UsageReporter is an interface for retrieving the token usage and the spend of the language model.
+type UsageReporter interface {
+	Totals(ctx context.Context, since time.Time) ([]models.UsageTotal, error)
+	ArticleTotals(ctx context.Context, articleID int64) ([]models.UsageTotal, error)
+}
+
+// ViewCmdUsage creates a bot command handler for showing the spend on the language model.
+// Without arguments it shows the usage per model today and this month against the budgets,
+// where a zero budget means no limit. With an article ID it shows the usage of the article.
+func ViewCmdUsage(reporter UsageReporter, dailyBudget, monthlyBudget float64) botkit.ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		var msgText string
+
+		if args := strings.TrimSpace(update.Message.CommandArguments()); args != "" {
+			id, err := strconv.ParseInt(args, 10, 64)
+			if err != nil {
+				return err
+			}
+
+			totals, err := reporter.ArticleTotals(ctx, id)
+			if err != nil {
+				return err
+			}
+
+			msgText = formatUsage(fmt.Sprintf("Article %d", id), totals, 0)
+		} else {
+			now := time.Now().UTC()
+
+			today, err := reporter.Totals(ctx, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC))
+			if err != nil {
+				return err
+			}
+
+			month, err := reporter.Totals(ctx, time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC))
+			if err != nil {
+				return err
+			}
+
+			msgText = formatUsage("Today", today, dailyBudget) + "\n\n" + formatUsage(now.Format("January 2006"), month, monthlyBudget)
+		}
+
+		reply := tgbotapi.NewMessage(update.Message.Chat.ID, msgText)
+		reply.ParseMode = parseModeMarkdownV2
+
+		if _, err := bot.Send(reply); err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
+
+// formatUsage formats the usage of a period per model into a Markdown-compatible string.
+func formatUsage(period string, totals []models.UsageTotal, budget float64) string {
+	var spend float64
+
+	lines := make([]string, 0, len(totals))
+
+	for _, total := range totals {
+		spend += total.Cost
+
+		lines = append(lines, markup.EscapeForMarkdown(fmt.Sprintf(
+			"• %s: %d requests, %d prompt and %d completion tokens, $%.4f",
+			total.Model,
+			total.Requests,
+			total.PromptTokens,
+			total.CompletionTokens,
+			total.Cost,
+		)))
+	}
+
+	header := fmt.Sprintf("$%.4f", spend)
+	if budget > 0 {
+		header += fmt.Sprintf(" of $%.2f", budget)
+	}
+
+	if len(lines) == 0 {
+		lines = append(lines, markup.EscapeForMarkdown("No requests"))
+	}
+
+	return fmt.Sprintf("*%s*: %s\n%s", markup.EscapeForMarkdown(period), markup.EscapeForMarkdown(header), strings.Join(lines, "\n"))
+}
@@ -2,6 +2,7 @@ package botkit
 
 import (
 	"encoding/json"
+	"strings"
 )
 
 // ParseJSON parses a JSON-encoded string into a specified generic type.
@@ -26,3 +27,10 @@ func ParseJSON[T any](src string) (T, error) {
 
 	return args, nil
 }
+
+// CallbackPayload returns the payload of callback data of the form "<prefix>:<payload>".
+func CallbackPayload(data string) string {
+	_, payload, _ := strings.Cut(data, ":")
+
+	return payload
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"runtime/debug"
+	"strings"
 	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
@@ -12,8 +13,9 @@ import (
 
 // Bot represents a wrapper around the Telegram Bot API with command handling functionality.
 type Bot struct {
-	api      *tgbotapi.BotAPI
-	cmdViems map[string]ViewFunc
+	api           *tgbotapi.BotAPI
+	cmdViems      map[string]ViewFunc
+	callbackViews map[string]ViewFunc
 }
 
 // ViewFunc defines a function type for handling Telegram updates.
@@ -39,6 +41,16 @@ func (b *Bot) RegisterCommand(name string, view ViewFunc) {
 	b.cmdViems[name] = view
 }
 
+// RegisterCallback registers a view function for callback queries of inline keyboard buttons.
+// The view is invoked for callback data of the form "<prefix>:<payload>".
+func (b *Bot) RegisterCallback(prefix string, view ViewFunc) {
+	if b.callbackViews == nil {
+		b.callbackViews = make(map[string]ViewFunc)
+	}
+
+	b.callbackViews[prefix] = view
+}
+
 // handleUpdate processes a single Telegram update, invoking the appropriate view function.
 func (b *Bot) handleUpdate(ctx context.Context, update tgbotapi.Update) {
 	const op = "bot.handleUpdate"
@@ -49,21 +61,31 @@ func (b *Bot) handleUpdate(ctx context.Context, update tgbotapi.Update) {
 		}
 	}()
 
-	if update.Message == nil || !update.Message.IsCommand() {
-		return
-	}
-
 	var view ViewFunc
 
-	cmd := update.Message.Command()
+	switch {
+	case update.CallbackQuery != nil:
+		prefix, _, _ := strings.Cut(update.CallbackQuery.Data, ":")
 
-	cmdView, ok := b.cmdViems[cmd]
-	if !ok {
+		callbackView, ok := b.callbackViews[prefix]
+		if !ok {
+			return
+		}
+
+		view = callbackView
+	case update.Message != nil && update.Message.IsCommand():
+		cmd := update.Message.Command()
+
+		cmdView, ok := b.cmdViems[cmd]
+		if !ok {
+			return
+		}
+
+		view = cmdView
+	default:
 		return
 	}
 
-	view = cmdView
-
 	if err := view(ctx, b.api, update); err != nil {
 		log.Printf("%s: %v", op, err)
 	}
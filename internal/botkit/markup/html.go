@@ -0,0 +1,8 @@
+package markup
+
+import "html"
+
+// EscapeForHTML escapes the characters that have special meaning in Telegram's HTML parse mode.
+func EscapeForHTML(src string) string {
+	return html.EscapeString(src)
+}
@@ -7,6 +7,8 @@ var (
 	// Each special character in MarkdownV2 needs to be prefixed with a backslash (`\`) to ensure
 	// it is displayed as plain text rather than being interpreted as formatting syntax.
 	replacer = strings.NewReplacer(
+		"\\",
+		"\\\\",
 		"-",
 		"\\-",
 		"_",
@@ -0,0 +1,22 @@
+package markup
+
+// Parse modes supported by the Telegram Bot API.
+const (
+	ParseModeMarkdownV2 = "MarkdownV2"
+	ParseModeHTML       = "HTML"
+	// ParseModeText sends messages as plain text, without any formatting.
+	ParseModeText = ""
+)
+
+// Escaper returns the escaping function for the given parse mode.
+// Unknown parse modes are treated as plain text and are not escaped.
+func Escaper(parseMode string) func(string) string {
+	switch parseMode {
+	case ParseModeMarkdownV2:
+		return EscapeForMarkdown
+	case ParseModeHTML:
+		return EscapeForHTML
+	default:
+		return func(src string) string { return src }
+	}
+}
@@ -0,0 +1,63 @@
+package botkit
+
+import (
+	"context"
+	"encoding/json"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// linkPreview holds the link_preview_options of a message, which the Telegram Bot API library does not support.
+type linkPreview struct {
+	URL              string `json:"url"`
+	PreferLargeMedia bool   `json:"prefer_large_media"`
+}
+
+// SendWithPreview sends the text message with the link preview of the given URL, which does not have to be
+// the first link of the text. It honors flood control like Send.
+func SendWithPreview(ctx context.Context, bot *tgbotapi.BotAPI, msg tgbotapi.MessageConfig, previewURL string) (tgbotapi.Message, error) {
+	params := tgbotapi.Params{}
+	params.AddNonZero64("chat_id", msg.ChatID)
+	params.AddNonEmpty("text", msg.Text)
+	params.AddNonEmpty("parse_mode", msg.ParseMode)
+	params.AddBool("disable_notification", msg.DisableNotification)
+
+	return requestWithPreview(ctx, bot, "sendMessage", params, previewURL)
+}
+
+// EditWithPreview edits the text of the message, showing the link preview of the given URL.
+// It honors flood control like Send.
+func EditWithPreview(ctx context.Context, bot *tgbotapi.BotAPI, edit tgbotapi.EditMessageTextConfig, previewURL string) (tgbotapi.Message, error) {
+	params := tgbotapi.Params{}
+	params.AddNonZero64("chat_id", edit.ChatID)
+	params.AddNonZero("message_id", edit.MessageID)
+	params["text"] = edit.Text
+	params.AddNonEmpty("parse_mode", edit.ParseMode)
+
+	return requestWithPreview(ctx, bot, "editMessageText", params, previewURL)
+}
+
+// requestWithPreview makes the request of the method with the link preview of the given URL.
+func requestWithPreview(
+	ctx context.Context,
+	bot *tgbotapi.BotAPI,
+	method string,
+	params tgbotapi.Params,
+	previewURL string,
+) (tgbotapi.Message, error) {
+	if err := params.AddInterface("link_preview_options", linkPreview{URL: previewURL, PreferLargeMedia: true}); err != nil {
+		return tgbotapi.Message{}, err
+	}
+
+	return retry(ctx, func() (tgbotapi.Message, error) {
+		resp, err := bot.MakeRequest(method, params)
+		if err != nil {
+			return tgbotapi.Message{}, err
+		}
+
+		var msg tgbotapi.Message
+		err = json.Unmarshal(resp.Result, &msg)
+
+		return msg, err
+	})
+}
@@ -0,0 +1,68 @@
+package botkit
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// maxFloodWaits is the number of times a request is repeated after Telegram's flood control kicks in.
+const maxFloodWaits = 3
+
+// RetryAfter returns how long Telegram asked to wait before repeating a request.
+// It returns zero if the error is not a flood control (HTTP 429) error.
+func RetryAfter(err error) time.Duration {
+	var tgErr *tgbotapi.Error
+	if !errors.As(err, &tgErr) || tgErr.RetryAfter <= 0 {
+		return 0
+	}
+
+	return time.Duration(tgErr.RetryAfter) * time.Second
+}
+
+// IsNotModified reports whether the error is Telegram's refusal to edit a message to the content it already has.
+// Such an edit has the outcome it asked for, so it can be treated as a success.
+func IsNotModified(err error) bool {
+	var tgErr *tgbotapi.Error
+
+	return errors.As(err, &tgErr) && strings.Contains(tgErr.Message, "message is not modified")
+}
+
+// Send sends the message and honors flood control: when Telegram responds
+// with retry_after, it waits for the requested time and repeats the request.
+// It gives up when the context is canceled.
+func Send(ctx context.Context, bot *tgbotapi.BotAPI, c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	return retry(ctx, func() (tgbotapi.Message, error) {
+		return bot.Send(c)
+	})
+}
+
+// retry makes the request and repeats it after the time Telegram asked to wait when flood control kicks in.
+// It gives up when the context is canceled.
+func retry(ctx context.Context, request func() (tgbotapi.Message, error)) (tgbotapi.Message, error) {
+	var (
+		msg tgbotapi.Message
+		err error
+	)
+
+	for i := 0; ; i++ {
+		msg, err = request()
+
+		wait := RetryAfter(err)
+		if wait == 0 || i == maxFloodWaits {
+			return msg, err
+		}
+
+		log.Printf("[WARN] flood control exceeded, retrying in %s", wait)
+
+		select {
+		case <-ctx.Done():
+			return msg, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package extractor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-shiori/go-readability"
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+	"github.com/kirinyoku/echo-wire-bot/internal/page"
+)
+
+// workers is the number of article pages downloaded concurrently.
+const workers = 4
+
+var redundantNewLines = regexp.MustCompile(`\n{3,}`)
+
+// ContentStorage defines the interface for storing the extracted content of articles.
+type ContentStorage interface {
+	AllNotExtracted(ctx context.Context, maxAttempts int, limit uint64) ([]models.Article, error)
+	StoreContent(ctx context.Context, content models.ArticleContent) error
+	MarkExtractionFailed(ctx context.Context, articleID int64, reason string) error
+}
+
+// PageFetcher defines the interface for downloading article pages.
+type PageFetcher interface {
+	Fetch(ctx context.Context, rawURL string) (page.Page, error)
+}
+
+// Extractor downloads the pages of new articles ahead of posting and stores their readable content,
+// so that posting never waits on the network.
+type Extractor struct {
+	contents ContentStorage
+	pages    PageFetcher
+
+	interval    time.Duration
+	batchSize   uint64
+	maxAttempts int
+}
+
+// New creates a new Extractor instance with the provided dependencies and configuration.
+func New(contents ContentStorage, pages PageFetcher, interval time.Duration, batchSize uint64, maxAttempts int) *Extractor {
+	return &Extractor{
+		contents:    contents,
+		pages:       pages,
+		interval:    interval,
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Run starts the Extractor to periodically extract the content of new articles.
+// It blocks until the context is canceled.
+func (e *Extractor) Run(ctx context.Context) error {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	e.extractLogged(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			e.extractLogged(ctx)
+		}
+	}
+}
+
+// extractLogged runs a single round of Extract and logs its error.
+func (e *Extractor) extractLogged(ctx context.Context) {
+	if err := e.Extract(ctx); err != nil && ctx.Err() == nil {
+		log.Printf("[ERROR] failed to extract articles: %v", err)
+	}
+}
+
+// Extract extracts the content of a batch of articles concurrently.
+// Failures of individual articles are recorded and retried in later rounds.
+func (e *Extractor) Extract(ctx context.Context) error {
+	const op = "extractor.Extract"
+
+	articles, err := e.contents.AllNotExtracted(ctx, e.maxAttempts, e.batchSize)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, workers)
+	)
+
+	for _, article := range articles {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(article models.Article) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := e.extractArticle(ctx, article); err != nil {
+				log.Printf("[WARN] failed to extract article %d (%s): %v", article.ID, article.Link, err)
+
+				if err := e.contents.MarkExtractionFailed(ctx, article.ID, err.Error()); err != nil {
+					log.Printf("[ERROR] failed to record extraction failure of article %d: %v", article.ID, err)
+				}
+			}
+		}(article)
+	}
+
+	wg.Wait()
+
+	return nil
+}
+
+// extractArticle downloads the page of the article, parses it with readability and stores the result.
+func (e *Extractor) extractArticle(ctx context.Context, article models.Article) error {
+	p, err := e.pages.Fetch(ctx, article.Link)
+	if err != nil {
+		return err
+	}
+
+	doc, err := readability.FromReader(bytes.NewReader(p.Body), p.URL)
+	if err != nil {
+		return err
+	}
+
+	text := CleanupText(doc.TextContent)
+
+	return e.contents.StoreContent(ctx, models.ArticleContent{
+		ArticleID: article.ID,
+		Text:      text,
+		HTML:      doc.Content,
+		Byline:    strings.TrimSpace(doc.Byline),
+		WordCount: len(strings.Fields(text)),
+		ImageURL:  doc.Image,
+		SiteName:  strings.TrimSpace(doc.SiteName),
+	})
+}
+
+// CleanupText removes redundant newlines and surrounding whitespace from the provided text.
+func CleanupText(text string) string {
+	return strings.TrimSpace(redundantNewLines.ReplaceAllString(text, "\n"))
+}
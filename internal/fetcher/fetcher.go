@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/kirinyoku/echo-wire-bot/internal/lang"
 	"github.com/kirinyoku/echo-wire-bot/internal/models"
 	"github.com/kirinyoku/echo-wire-bot/internal/source"
 )
@@ -36,6 +37,17 @@ type Fetcher struct {
 
 	fetchInterval  time.Duration
 	filterKeywords []string
+	dryRun         bool
+}
+
+// Option configures optional behavior of the Fetcher.
+type Option func(*Fetcher)
+
+// WithDryRun enables the dry-run mode, in which fetched articles are reported in the log instead of being stored.
+func WithDryRun(enabled bool) Option {
+	return func(f *Fetcher) {
+		f.dryRun = enabled
+	}
 }
 
 // New creates a new Fetcher instance with the provided dependencies and configuration.
@@ -44,13 +56,20 @@ func New(
 	source SourcesProvider,
 	fetchIntrerval time.Duration,
 	filterKeywords []string,
+	opts ...Option,
 ) *Fetcher {
-	return &Fetcher{
+	f := &Fetcher{
 		articles:       articles,
 		sources:        source,
 		fetchInterval:  fetchIntrerval,
 		filterKeywords: filterKeywords,
 	}
+
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	return f
 }
 
 // Run starts the Fetcher to periodically fetch articles from sources.
@@ -121,14 +140,27 @@ func (f *Fetcher) Fetch(ctx context.Context) error {
 }
 
 // processItem processes a batch of items fetched from a single source.
-// It stores valid items in the storage layer.
+// It stores valid items in the storage layer, or reports them in dry-run mode.
 func (f *Fetcher) processItem(ctx context.Context, s Source, items []models.Item) error {
 	const op = "fetcher.processItem"
 
+	var skipped int
+
 	for _, item := range items {
 		item.Date = item.Date.UTC()
 
 		if f.itemShouldBeSkipped(item) {
+			skipped++
+
+			if f.dryRun {
+				log.Printf("[DRY RUN] source %s: would skip %q (%s)", s.Name(), item.Title, item.Link)
+			}
+
+			continue
+		}
+
+		if f.dryRun {
+			log.Printf("[DRY RUN] source %s: would store %q (%s), published at %s", s.Name(), item.Title, item.Link, item.Date.Format(time.RFC3339))
 			continue
 		}
 
@@ -137,12 +169,19 @@ func (f *Fetcher) processItem(ctx context.Context, s Source, items []models.Item
 			Title:       item.Title,
 			Link:        item.Link,
 			Summary:     item.Summary,
+			ImageURL:    item.ImageURL,
+			Categories:  item.Categories,
+			Language:    lang.Detect(item.Title + "\n" + item.Summary),
 			PublishedAt: item.Date,
 		}); err != nil {
 			return fmt.Errorf("%s: %w", op, err)
 		}
 	}
 
+	if f.dryRun {
+		log.Printf("[DRY RUN] source %s: %d items would be stored unless already known, %d skipped by filters", s.Name(), len(items)-skipped, skipped)
+	}
+
 	return nil
 }
 
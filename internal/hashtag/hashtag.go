@@ -0,0 +1,117 @@
+// Package hashtag builds Telegram hashtags from article metadata.
+package hashtag
+
+import (
+	"strings"
+	"unicode"
+)
+
+// maxLength is the maximum length of a hashtag in characters, without the leading '#'.
+const maxLength = 32
+
+// Generator turns candidate phrases, such as feed categories or named entities, into hashtags.
+type Generator struct {
+	limit    int
+	synonyms map[string]string
+}
+
+// New creates a new Generator.
+// Parameters:
+// - limit: The maximum number of hashtags per post.
+// - synonyms: Maps phrases to the hashtag used in their place, e.g. "AI" to "ArtificialIntelligence".
+// Keys are matched case-insensitively after normalization; an empty value drops the phrase.
+func New(limit int, synonyms map[string]string) *Generator {
+	normalized := make(map[string]string, len(synonyms))
+
+	for from, to := range synonyms {
+		if key := Normalize(from); key != "" {
+			normalized[strings.ToLower(key)] = Normalize(to)
+		}
+	}
+
+	return &Generator{
+		limit:    limit,
+		synonyms: normalized,
+	}
+}
+
+// Hashtags returns the hashtags for the candidate phrases, in order, with the leading '#'.
+// Phrases that cannot form a hashtag are skipped, duplicates are removed case-insensitively,
+// and the result is capped at the limit of the Generator.
+func (g *Generator) Hashtags(candidates ...string) []string {
+	var (
+		tags = make([]string, 0, g.limit)
+		seen = make(map[string]struct{}, g.limit)
+	)
+
+	for _, candidate := range candidates {
+		if len(tags) >= g.limit {
+			break
+		}
+
+		tag := Normalize(candidate)
+		if tag == "" {
+			continue
+		}
+
+		if synonym, ok := g.synonyms[strings.ToLower(tag)]; ok {
+			if tag = synonym; tag == "" {
+				continue
+			}
+		}
+
+		key := strings.ToLower(tag)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+
+		seen[key] = struct{}{}
+		tags = append(tags, "#"+tag)
+	}
+
+	return tags
+}
+
+// Normalize converts a phrase into the body of a hashtag following Telegram rules:
+// only letters, digits and underscores are kept, and words are joined in CamelCase,
+// e.g. "New York" becomes "NewYork" and "COVID-19" becomes "COVID19".
+// It returns an empty string if the phrase has no letters, as Telegram does not
+// recognize hashtags made of digits only.
+func Normalize(phrase string) string {
+	var (
+		b         strings.Builder
+		length    int
+		newWord   = true
+		hasLetter bool
+	)
+
+	for _, r := range strings.TrimPrefix(strings.TrimSpace(phrase), "#") {
+		if length >= maxLength {
+			break
+		}
+
+		switch {
+		case unicode.IsLetter(r):
+			if newWord {
+				r = unicode.ToUpper(r)
+			}
+
+			hasLetter = true
+			newWord = false
+		case unicode.IsDigit(r) || r == '_':
+			newWord = false
+		default:
+			newWord = true
+			continue
+		}
+
+		b.WriteRune(r)
+		length++
+	}
+
+	if !hasLetter {
+		return ""
+	}
+
+	return b.String()
+}
@@ -0,0 +1,133 @@
+package hashtag
+
+import (
+	"reflect"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name   string
+		phrase string
+		want   string
+	}{
+		{"words", "New York", "NewYork"},
+		{"surrounding spaces", "  world news  ", "WorldNews"},
+		{"leading hash", "#Politics", "Politics"},
+		{"hyphen and digits", "COVID-19", "COVID19"},
+		{"digits before letters", "3d printing", "3dPrinting"},
+		{"punctuation", "Rock'n'roll!", "RockNRoll"},
+		{"underscore", "snake_case", "Snake_case"},
+		{"cyrillic", "искусственный интеллект", "ИскусственныйИнтеллект"},
+		{"cyrillic with hyphen", "Санкт-Петербург", "СанктПетербург"},
+		{"digits only", "2024", ""},
+		{"punctuation only", "!!! ...", ""},
+		{"empty", "", ""},
+		{"long phrase", "The quick brown fox jumps over the lazy dog again", "TheQuickBrownFoxJumpsOverTheLazy"},
+		{"long cyrillic phrase", "Министерство иностранных дел Российской Федерации", "МинистерствоИностранныхДелРоссий"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Normalize(tt.phrase)
+			if got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.phrase, got, tt.want)
+			}
+
+			if n := utf8.RuneCountInString(got); n > maxLength {
+				t.Errorf("Normalize(%q) is %d characters long, want at most %d", tt.phrase, n, maxLength)
+			}
+		})
+	}
+}
+
+func TestGeneratorHashtags(t *testing.T) {
+	tests := []struct {
+		name       string
+		limit      int
+		synonyms   map[string]string
+		candidates []string
+		want       []string
+	}{
+		{
+			name:       "camel case and order",
+			limit:      5,
+			candidates: []string{"BBC News", "world", "Санкт-Петербург"},
+			want:       []string{"#BBCNews", "#World", "#СанктПетербург"},
+		},
+		{
+			name:       "invalid phrases are skipped",
+			limit:      5,
+			candidates: []string{"2024", "", "—", "Economy"},
+			want:       []string{"#Economy"},
+		},
+		{
+			name:       "duplicates are removed case-insensitively",
+			limit:      5,
+			candidates: []string{"BBC News", "bbc news", "BBC-News", "Москва", "москва"},
+			want:       []string{"#BBCNews", "#Москва"},
+		},
+		{
+			name:       "limit",
+			limit:      2,
+			candidates: []string{"One", "Two", "Three"},
+			want:       []string{"#One", "#Two"},
+		},
+		{
+			name:       "limit counts only kept hashtags",
+			limit:      2,
+			candidates: []string{"One", "2024", "one", "Two", "Three"},
+			want:       []string{"#One", "#Two"},
+		},
+		{
+			name:       "zero limit",
+			limit:      0,
+			candidates: []string{"One"},
+			want:       []string{},
+		},
+		{
+			name:       "synonyms",
+			limit:      5,
+			synonyms:   map[string]string{"AI": "Artificial Intelligence", "ии": "ИскусственныйИнтеллект"},
+			candidates: []string{"ai", "ИИ"},
+			want:       []string{"#ArtificialIntelligence", "#ИскусственныйИнтеллект"},
+		},
+		{
+			name:       "synonym keys are normalized",
+			limit:      5,
+			synonyms:   map[string]string{"artificial-intelligence": "AI"},
+			candidates: []string{"Artificial Intelligence"},
+			want:       []string{"#AI"},
+		},
+		{
+			name:       "synonym duplicates an earlier hashtag",
+			limit:      5,
+			synonyms:   map[string]string{"Artificial Intelligence": "AI"},
+			candidates: []string{"AI", "Artificial Intelligence", "Tech"},
+			want:       []string{"#AI", "#Tech"},
+		},
+		{
+			name:       "empty synonym drops the phrase",
+			limit:      5,
+			synonyms:   map[string]string{"Uncategorized": ""},
+			candidates: []string{"Uncategorized", "Sports"},
+			want:       []string{"#Sports"},
+		},
+		{
+			name:       "long phrase is capped",
+			limit:      5,
+			candidates: []string{"The quick brown fox jumps over the lazy dog again"},
+			want:       []string{"#TheQuickBrownFoxJumpsOverTheLazy"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New(tt.limit, tt.synonyms).Hashtags(tt.candidates...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Hashtags(%q) = %q, want %q", tt.candidates, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+// Package lang provides a lightweight, offline language detection for article texts.
+package lang
+
+import (
+	"strings"
+	"unicode"
+)
+
+// minScore is the minimum number of matched markers required to report a language.
+const minScore = 2
+
+// names maps supported ISO 639-1 language codes to their English names.
+var names = map[string]string{
+	"en": "English",
+	"de": "German",
+	"fr": "French",
+	"es": "Spanish",
+	"pl": "Polish",
+	"uk": "Ukrainian",
+	"ru": "Russian",
+}
+
+// stopWords holds frequent words that are characteristic of each language.
+var stopWords = map[string][]string{
+	"en": {"the", "and", "of", "to", "in", "is", "that", "for", "it", "with", "was", "on", "as", "are", "this", "by", "from", "has", "have", "be"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "mit", "den", "von", "zu", "sich", "des", "auf", "für", "im", "dem", "ein", "eine", "wird", "auch"},
+	"fr": {"le", "la", "les", "et", "des", "est", "une", "un", "du", "dans", "que", "pour", "qui", "pas", "sur", "au", "avec", "sont", "ce", "par"},
+	"es": {"el", "los", "las", "y", "que", "del", "en", "una", "un", "por", "con", "para", "es", "se", "su", "al", "lo", "como", "más", "pero"},
+	"pl": {"i", "w", "nie", "na", "się", "jest", "że", "do", "z", "to", "jak", "po", "od", "przez", "dla", "ale", "czy", "jego", "oraz", "być"},
+	"uk": {"і", "в", "на", "що", "не", "з", "до", "та", "це", "як", "у", "за", "від", "його", "її", "він", "вона", "але", "або", "було"},
+	"ru": {"и", "в", "на", "что", "не", "с", "по", "это", "как", "к", "за", "от", "его", "он", "она", "но", "или", "было", "из", "который"},
+}
+
+// markers holds letters that occur in only one of the supported languages of the same script.
+var markers = map[string]string{
+	"de": "äöüß",
+	"fr": "àâçèêëîïôœùû",
+	"es": "ñ¿¡áíóú",
+	"pl": "ąćęłńśźż",
+	"uk": "іїєґ",
+	"ru": "ыэъё",
+}
+
+// stopWordSets is the lookup form of stopWords.
+var stopWordSets = func() map[string]map[string]struct{} {
+	sets := make(map[string]map[string]struct{}, len(stopWords))
+
+	for code, words := range stopWords {
+		set := make(map[string]struct{}, len(words))
+		for _, word := range words {
+			set[word] = struct{}{}
+		}
+
+		sets[code] = set
+	}
+
+	return sets
+}()
+
+// Detect returns the ISO 639-1 code of the language of the text,
+// or an empty string if the language cannot be detected with confidence.
+func Detect(text string) string {
+	var (
+		scores   = make(map[string]int, len(names))
+		cyrillic int
+		latin    int
+	)
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\'' && r != '’'
+	})
+
+	for _, word := range words {
+		for code, set := range stopWordSets {
+			if _, ok := set[word]; ok {
+				scores[code]++
+			}
+		}
+
+		for _, r := range word {
+			switch {
+			case unicode.Is(unicode.Cyrillic, r):
+				cyrillic++
+			case unicode.Is(unicode.Latin, r):
+				latin++
+			}
+
+			for code, letters := range markers {
+				if strings.ContainsRune(letters, r) {
+					scores[code]++
+				}
+			}
+		}
+	}
+
+	script := unicode.Latin
+	if cyrillic > latin {
+		script = unicode.Cyrillic
+	}
+
+	var (
+		best      string
+		bestScore int
+	)
+
+	for code, score := range scores {
+		if !isScript(code, script) {
+			continue
+		}
+
+		if score > bestScore || (score == bestScore && code < best) {
+			best, bestScore = code, score
+		}
+	}
+
+	if bestScore < minScore {
+		return ""
+	}
+
+	return best
+}
+
+// Name returns the English name of the language with the given ISO 639-1 code.
+// Unknown codes are returned as is.
+func Name(code string) string {
+	if name, ok := names[strings.ToLower(code)]; ok {
+		return name
+	}
+
+	return code
+}
+
+// Same reports whether the two language codes denote the same language.
+func Same(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
+
+// isScript reports whether the language is written in the given script.
+func isScript(code string, script *unicode.RangeTable) bool {
+	switch code {
+	case "uk", "ru":
+		return script == unicode.Cyrillic
+	default:
+		return script == unicode.Latin
+	}
+}
+
+// IsStopWord reports whether the lowercase word is one of the frequent words of the language
+// that carry little meaning, such as articles and conjunctions.
+func IsStopWord(code, word string) bool {
+	_, ok := stopWordSets[strings.ToLower(code)][word]
+	return ok
+}
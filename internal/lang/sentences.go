@@ -0,0 +1,208 @@
+package lang
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// abbreviations holds the lowercase abbreviations of each language that end with a period
+// without ending the sentence, written without the final period.
+var abbreviations = map[string][]string{
+	"en": {"mr", "mrs", "ms", "dr", "prof", "sr", "jr", "st", "vs", "etc", "inc", "ltd", "co", "corp", "gen", "gov",
+		"sen", "rep", "lt", "col", "sgt", "capt", "jan", "feb", "mar", "apr", "jun", "jul", "aug", "sep", "sept",
+		"oct", "nov", "dec", "no", "fig", "approx", "e.g", "i.e", "u.s", "u.k", "a.m", "p.m"},
+	"de": {"z.b", "usw", "bzw", "ca", "dr", "prof", "nr", "str", "evtl", "ggf", "vgl", "sog", "inkl", "d.h", "u.a",
+		"z.t", "mio", "mrd", "jh", "abs"},
+	"fr": {"m", "mme", "mlle", "dr", "pr", "etc", "av", "bd", "st", "cf", "p.ex", "env", "mds"},
+	"es": {"sr", "sra", "srta", "dr", "dra", "ud", "uds", "etc", "pág", "núm", "aprox", "avda", "ee.uu"},
+	"pl": {"np", "tzn", "itd", "itp", "dr", "prof", "mgr", "inż", "ul", "al", "tj", "m.in", "ok", "godz", "tys",
+		"mln", "mld", "zł", "proc"},
+	"uk": {"т.д", "т.п", "ім", "вул", "див", "ст", "тис", "млн", "млрд", "проф", "д-р", "грн", "обл"},
+	"ru": {"т.д", "т.п", "т.е", "им", "ул", "см", "ст", "тыс", "млн", "млрд", "проф", "д-р", "руб", "обл"},
+}
+
+// ordinalPeriod holds the languages that write ordinal numbers with a period, e.g. "3. Oktober".
+var ordinalPeriod = map[string]struct{}{"de": {}, "pl": {}}
+
+// abbreviationSets is the lookup form of abbreviations.
+var abbreviationSets = func() map[string]map[string]struct{} {
+	sets := make(map[string]map[string]struct{}, len(abbreviations))
+
+	for code, words := range abbreviations {
+		set := make(map[string]struct{}, len(words))
+		for _, word := range words {
+			set[word] = struct{}{}
+		}
+
+		sets[code] = set
+	}
+
+	return sets
+}()
+
+// Sentences splits the text into sentences. Line breaks always end a sentence, while
+// periods after the abbreviations and ordinal numbers of the language do not.
+// An empty or unknown code uses the rules shared by all languages.
+func Sentences(text, code string) []string {
+	var sentences []string
+
+	for _, line := range strings.Split(text, "\n") {
+		sentences = append(sentences, splitLine(strings.TrimSpace(line), strings.ToLower(code))...)
+	}
+
+	return sentences
+}
+
+// splitLine splits a line of text into sentences.
+func splitLine(line, code string) []string {
+	var (
+		sentences []string
+		runes     = []rune(line)
+		start     int
+	)
+
+	for i := 0; i < len(runes); i++ {
+		if !isTerminator(runes[i]) {
+			continue
+		}
+
+		last, end := runes[i], i+1
+		for end < len(runes) && (isTerminator(runes[end]) || isClosing(runes[end])) {
+			if isTerminator(runes[end]) {
+				last = runes[end]
+			}
+
+			end++
+		}
+
+		i = end - 1
+
+		// Full-width terminators end the sentence even without a following space, e.g. in Chinese.
+		if end < len(runes) && !unicode.IsSpace(runes[end]) && !isFullWidth(last) {
+			continue
+		}
+
+		if last == '.' && !endsSentence(runes[start:end], runes[end:], code) {
+			continue
+		}
+
+		if sentence := strings.TrimSpace(string(runes[start:end])); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+
+		start = end
+	}
+
+	if sentence := strings.TrimSpace(string(runes[start:])); sentence != "" {
+		sentences = append(sentences, sentence)
+	}
+
+	return sentences
+}
+
+// endsSentence reports whether the period at the end of the text ends the sentence,
+// given the rest of the line after it.
+func endsSentence(text, rest []rune, code string) bool {
+	next := strings.TrimLeftFunc(string(rest), unicode.IsSpace)
+	if next == "" {
+		return true
+	}
+
+	first := []rune(next)[0]
+	if unicode.IsLower(first) {
+		return false
+	}
+
+	word := lastWord(text)
+	if word == "" {
+		return true
+	}
+
+	if len([]rune(word)) == 1 && unicode.IsLetter([]rune(word)[0]) {
+		// An initial, e.g. "J. Smith".
+		return false
+	}
+
+	if _, ok := abbreviationSets[code][strings.ToLower(word)]; ok {
+		return false
+	}
+
+	if _, ok := ordinalPeriod[code]; ok && isNumber(word) {
+		return false
+	}
+
+	return true
+}
+
+// lastWord returns the last word of the text without the final periods and the surrounding punctuation.
+func lastWord(text []rune) string {
+	s := strings.TrimRightFunc(string(text), func(r rune) bool {
+		return r == '.' || isClosing(r)
+	})
+
+	if i := strings.LastIndexFunc(s, unicode.IsSpace); i >= 0 {
+		s = s[i+1:]
+	}
+
+	return strings.TrimLeftFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Join joins the sentences of a line split by Sentences with spaces,
+// except after full-width terminators, which are written without a following space.
+func Join(sentences []string) string {
+	var b strings.Builder
+
+	for i, sentence := range sentences {
+		if i > 0 {
+			if r, _ := utf8.DecodeLastRuneInString(sentences[i-1]); !isFullWidth(r) {
+				b.WriteString(" ")
+			}
+		}
+
+		b.WriteString(sentence)
+	}
+
+	return b.String()
+}
+
+// IsComplete reports whether the sentence ends with a sentence terminator,
+// possibly followed by closing quotes or brackets.
+func IsComplete(sentence string) bool {
+	sentence = strings.TrimRightFunc(strings.TrimSpace(sentence), isClosing)
+	if sentence == "" {
+		return false
+	}
+
+	r, _ := utf8.DecodeLastRuneInString(sentence)
+
+	return isTerminator(r)
+}
+
+// isTerminator reports whether the rune can end a sentence.
+func isTerminator(r rune) bool {
+	return strings.ContainsRune(".!?…。！？؟।", r)
+}
+
+// isFullWidth reports whether the terminator is written without a following space.
+func isFullWidth(r rune) bool {
+	return strings.ContainsRune("。！？", r)
+}
+
+// isClosing reports whether the rune is a closing quote or bracket that may follow the end of a sentence.
+func isClosing(r rune) bool {
+	return strings.ContainsRune(`"')]»”’`, r)
+}
+
+// isNumber reports whether the word consists of digits only.
+func isNumber(word string) bool {
+	for _, r := range word {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+
+	return word != ""
+}
@@ -0,0 +1,146 @@
+package lang
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSentences(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		code string
+		want []string
+	}{
+		{
+			name: "simple",
+			text: "The vote passed. Turnout was high! Will it last?",
+			code: "en",
+			want: []string{"The vote passed.", "Turnout was high!", "Will it last?"},
+		},
+		{
+			name: "abbreviations",
+			text: "Dr. Smith met Mr. Jones in the U.S. yesterday. They talked.",
+			code: "en",
+			want: []string{"Dr. Smith met Mr. Jones in the U.S. yesterday.", "They talked."},
+		},
+		{
+			name: "abbreviation before a lowercase word",
+			text: "Prices rose, e.g. for bread and milk. Wages did not.",
+			code: "en",
+			want: []string{"Prices rose, e.g. for bread and milk.", "Wages did not."},
+		},
+		{
+			name: "initials",
+			text: "The report by J. Smith was published. It was long.",
+			code: "en",
+			want: []string{"The report by J. Smith was published.", "It was long."},
+		},
+		{
+			name: "decimals",
+			text: "Growth reached 3.5 percent. Inflation fell to 2.1.",
+			code: "en",
+			want: []string{"Growth reached 3.5 percent.", "Inflation fell to 2.1."},
+		},
+		{
+			name: "ordinals",
+			text: "Die Wahl findet am 3. Oktober statt. Alle sind eingeladen.",
+			code: "de",
+			want: []string{"Die Wahl findet am 3. Oktober statt.", "Alle sind eingeladen."},
+		},
+		{
+			name: "numbers end sentences in languages without ordinal periods",
+			text: "The score was 3. Fans cheered.",
+			code: "en",
+			want: []string{"The score was 3.", "Fans cheered."},
+		},
+		{
+			name: "language abbreviations",
+			text: "Das gilt z.B. für Berlin. Andere Städte folgen.",
+			code: "de",
+			want: []string{"Das gilt z.B. für Berlin.", "Andere Städte folgen."},
+		},
+		{
+			name: "closing quotes",
+			text: `He said: "It is over." Then he left.`,
+			code: "en",
+			want: []string{`He said: "It is over."`, "Then he left."},
+		},
+		{
+			name: "repeated terminators",
+			text: "Really?! Yes... It happened.",
+			code: "en",
+			want: []string{"Really?!", "Yes...", "It happened."},
+		},
+		{
+			name: "CJK terminators",
+			text: "会议已经结束。结果将在明天公布！你知道吗？",
+			code: "zh",
+			want: []string{"会议已经结束。", "结果将在明天公布！", "你知道吗？"},
+		},
+		{
+			name: "line breaks",
+			text: "First line without a period\nSecond line.",
+			code: "en",
+			want: []string{"First line without a period", "Second line."},
+		},
+		{
+			name: "unfinished",
+			text: "The first sentence. The second one is cut",
+			code: "",
+			want: []string{"The first sentence.", "The second one is cut"},
+		},
+		{
+			name: "empty",
+			text: " \n ",
+			code: "en",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sentences(tt.text, tt.code); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Sentences(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJoin(t *testing.T) {
+	tests := []struct {
+		sentences []string
+		want      string
+	}{
+		{[]string{"One.", "Two."}, "One. Two."},
+		{[]string{"会议已经结束。", "结果将在明天公布！"}, "会议已经结束。结果将在明天公布！"},
+		{nil, ""},
+	}
+
+	for _, tt := range tests {
+		if got := Join(tt.sentences); got != tt.want {
+			t.Errorf("Join(%q) = %q, want %q", tt.sentences, got, tt.want)
+		}
+	}
+}
+
+func TestIsComplete(t *testing.T) {
+	tests := []struct {
+		sentence string
+		want     bool
+	}{
+		{"It is over.", true},
+		{`He said: "It is over."`, true},
+		{"(It is over!)", true},
+		{"会议已经结束。", true},
+		{"It is over", false},
+		{"It is over,", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsComplete(tt.sentence); got != tt.want {
+			t.Errorf("IsComplete(%q) = %v, want %v", tt.sentence, got, tt.want)
+		}
+	}
+}
@@ -9,6 +9,7 @@ type Item struct {
 	Link       string
 	Date       time.Time
 	Summary    string
+	ImageURL   string
 	SourceName string
 }
 
@@ -21,14 +22,183 @@ type Source struct {
 	UpdatedAt time.Time
 }
 
+// ArticleStatus represents the delivery state of an article.
+type ArticleStatus string
+
+const (
+	// ArticleStatusPending marks articles waiting to be posted.
+	ArticleStatusPending ArticleStatus = "pending"
+	// ArticleStatusModeration marks articles waiting for a decision of a moderator.
+	ArticleStatusModeration ArticleStatus = "moderation"
+	// ArticleStatusApproved marks articles approved by a moderator and waiting to be posted.
+	ArticleStatusApproved ArticleStatus = "approved"
+	// ArticleStatusRejected marks articles rejected by a moderator.
+	ArticleStatusRejected ArticleStatus = "rejected"
+	// ArticleStatusSending marks articles claimed for posting whose delivery is not confirmed yet.
+	ArticleStatusSending ArticleStatus = "sending"
+	// ArticleStatusPosted marks articles that have been posted.
+	ArticleStatusPosted ArticleStatus = "posted"
+	// ArticleStatusRetracted marks posted articles whose channel message has been deleted.
+	ArticleStatusRetracted ArticleStatus = "retracted"
+	// ArticleStatusDead marks articles that failed to be posted too many times.
+	ArticleStatusDead ArticleStatus = "dead"
+	// ArticleStatusExpired marks articles that were not posted within the expiry window.
+	ArticleStatusExpired ArticleStatus = "expired"
+	// ArticleStatusFiltered marks articles skipped by the enrichment rules, e.g. for low relevance.
+	ArticleStatusFiltered ArticleStatus = "filtered"
+)
+
 // Article represents an individual article fetched from an RSS feed.
 type Article struct {
-	ID          int64
-	SourceID    int64
-	Title       string
-	Link        string
-	Summary     string
-	PublishedAt time.Time
-	PostedAt    time.Time
+	ID         int64
+	SourceID   int64
+	Title      string
+	Link       string
+	Summary    string
+	ImageURL   string
+	Categories []string
+	SourceName string
+	// Language is the ISO 639-1 code of the language the article is written in, if detected.
+	Language     string
+	Status       ArticleStatus
+	SendAttempts int
+	LastError    string
+	ChatID       int64
+	MessageID    int
+	PostSummary  string
+	// PostTitle is the translated title shown in the post. It is empty if the article is not translated.
+	PostTitle string
+	// Hashtags are the hashtags shown in the post, with the leading '#'.
+	Hashtags []string
+	// Urgency is the score of the breaking-news rules, from 0 to 1.
+	Urgency float64
+	// Breaking marks articles posted through the breaking-news fast lane.
+	Breaking bool
+	// TelegraphURL is the URL of the Telegraph page the article was published as, if any.
+	TelegraphURL string
+	// Enrichment is the structured analysis of the article. A zero EnrichedAt means it has not been enriched.
+	Enrichment Enrichment
+	// PromptVersion is the version of the prompt the post summary was generated with.
+	// It is empty if the summary was not generated by the language model, e.g. edited by an admin.
+	PromptVersion string
+	PostHasPhoto  bool
+	// ModerationChatID and ModerationMessageID identify the message the article was sent to moderators as.
+	ModerationChatID    int64
+	ModerationMessageID int
+	ModeratedAt         time.Time
+	PublishedAt         time.Time
+	PostedAt            time.Time
+	CreatedAt           time.Time
+}
+
+// Enrichment is the structured analysis of an article by the language model.
+type Enrichment struct {
+	Summary string
+	// Tags are the topics of the article.
+	Tags []string
+	// Relevance is how relevant the article is to the topic of the channel, from 0 to 1.
+	Relevance float64
+	// Clickbait marks articles whose title exaggerates or withholds the news.
+	Clickbait bool
+	// Sentiment is the tone of the article, from -1 (negative) to 1 (positive).
+	Sentiment float64
+	// PromptVersion is the version of the prompt the summary was generated with.
+	PromptVersion string
+	EnrichedAt    time.Time
+}
+
+// ArticleContent represents the readable content extracted from the page of an article.
+type ArticleContent struct {
+	ArticleID int64
+	Text      string
+	// HTML is the readable content of the page with its formatting and images.
+	HTML        string
+	Byline      string
+	WordCount   int
+	ImageURL    string
+	SiteName    string
+	ExtractedAt time.Time
+}
+
+// PostTemplate represents an admin-editable layout of channel posts.
+type PostTemplate struct {
+	Name      string
+	Body      string
+	UpdatedAt time.Time
+}
+
+// PromptScope is what a prompt template applies to.
+type PromptScope string
+
+const (
+	// PromptScopeGlobal marks the prompt used for all articles without a more specific prompt.
+	PromptScopeGlobal PromptScope = "global"
+	// PromptScopeSource marks prompts used for the articles of a source.
+	PromptScopeSource PromptScope = "source"
+	// PromptScopeChat marks prompts used for the articles posted to a destination chat.
+	PromptScopeChat PromptScope = "chat"
+)
+
+// Prompt represents a version of an admin-editable prompt template used to summarize articles.
+type Prompt struct {
+	ID    int64
+	Scope PromptScope
+	// ScopeID is the ID of the source or the chat the prompt applies to, or 0 for the global prompt.
+	ScopeID int64
+	// Version is the number of the version of the prompt within its scope, starting from 1.
+	Version   int
+	Body      string
+	CreatedAt time.Time
+}
+
+// TelegraphPage is a Telegraph page an article was published as.
+type TelegraphPage struct {
+	ArticleID int64
+	// AccessToken is the token of the Telegraph account that owns the page.
+	AccessToken string
+	Path        string
+	URL         string
 	CreatedAt   time.Time
 }
+
+// ExpiredCount is the number of articles of a source that expired without being posted.
+type ExpiredCount struct {
+	SourceName string `db:"source_name"`
+	Count      int    `db:"count"`
+}
+
+// CachedSummary is a summary stored for reuse, keyed by the hash of the normalized input text,
+// the model and the version of the prompt it was generated with.
+type CachedSummary struct {
+	TextHash         string
+	Model            string
+	PromptVersion    string
+	Summary          string
+	PromptTokens     int
+	CompletionTokens int
+	CreatedAt        time.Time
+}
+
+// LLMUsage is the token usage and the cost of a request to a language model.
+type LLMUsage struct {
+	ID int64
+	// ArticleID is the article the request was made for, or 0 if none.
+	ArticleID int64
+	Model     string
+	// Purpose is what the request was made for, e.g. "summary" or "translation".
+	Purpose          string
+	PromptTokens     int
+	CompletionTokens int
+	// Cost is the cost of the request in US dollars.
+	Cost      float64
+	CreatedAt time.Time
+}
+
+// UsageTotal is the total token usage and cost of the requests to a model.
+type UsageTotal struct {
+	Model            string  `db:"model"`
+	Requests         int     `db:"requests"`
+	PromptTokens     int     `db:"prompt_tokens"`
+	CompletionTokens int     `db:"completion_tokens"`
+	Cost             float64 `db:"cost"`
+}
@@ -0,0 +1,35 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/echo-wire-bot/internal/botkit"
+)
+
+// alertTimeout limits the time spent on sending an alert to the admin chat.
+const alertTimeout = 30 * time.Second
+
+// AlertBreakerOpen sends an alert to the admin chat that the language model keeps failing
+// and is skipped for the cooldown. Nothing is sent if alerts are disabled.
+// Its signature matches summary.AlertFunc.
+func (n *Notifier) AlertBreakerOpen(ctx context.Context, model string, err error, cooldown time.Duration) {
+	if n.alertChatID == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, alertTimeout)
+	defer cancel()
+
+	text := fmt.Sprintf(
+		"⚠️ Language model %s keeps failing and is skipped for %s, summaries are generated by the next model in the chain.\n\nLast error: %v",
+		model, shortDuration(cooldown), err,
+	)
+
+	if _, err := botkit.Send(ctx, n.bot, tgbotapi.NewMessage(n.alertChatID, text)); err != nil {
+		log.Printf("[ERROR] failed to send alert: %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+	"github.com/kirinyoku/echo-wire-bot/internal/summary"
+)
+
+// breakingBatchSize is the number of new articles checked against the breaking-news rules per round.
+const breakingBatchSize = 20
+
+// UrgencyScorer defines the interface for scoring how urgent the news of an article is.
+type UrgencyScorer interface {
+	// Urgency returns the urgency of the text, from 0 (not urgent) to 1 (breaking news).
+	Urgency(ctx context.Context, text string) (float64, error)
+}
+
+// BreakingRules define which articles are breaking news. An article is breaking news if its title or
+// summary contains one of the keywords as a whole word, case-insensitively, if it comes from one of the sources, or if its urgency
+// scored by the scorer reaches the threshold.
+type BreakingRules struct {
+	Keywords  []string
+	SourceIDs []int64
+	Scorer    UrgencyScorer
+	Threshold float64
+}
+
+// breaking holds the settings of the breaking-news fast lane.
+type breaking struct {
+	keywords   []*regexp.Regexp
+	sources    map[int64]struct{}
+	scorer     UrgencyScorer
+	threshold  float64
+	interval   time.Duration
+	maxAge     time.Duration
+	maxPerHour int
+	pin        bool
+	sound      bool
+}
+
+// runBreaking checks new articles for breaking news at the interval of the fast lane until the context is canceled.
+// An article is never summarized or posted twice, even if the regular queue picks it at the same time, since it is claimed first.
+func (n *Notifier) runBreaking(ctx context.Context) {
+	ticker := time.NewTicker(n.breaking.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			n.postBreakingLogged(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// postBreakingLogged runs a single round of PostBreaking and logs its error.
+func (n *Notifier) postBreakingLogged(ctx context.Context) {
+	if err := n.PostBreaking(ctx); err != nil && ctx.Err() == nil {
+		log.Printf("[ERROR] failed to post breaking news: %v", err)
+	}
+}
+
+// PostBreaking checks new articles against the breaking-news rules and posts breaking news immediately,
+// without waiting for the regular queue. Once the hourly cap of breaking news is reached,
+// further breaking news go through the regular queue like any other article.
+// In dry-run mode for the channel, the urgency of the articles is not stored.
+func (n *Notifier) PostBreaking(ctx context.Context) error {
+	const op = "notifier.PostBreaking"
+
+	if n.breaking == nil {
+		return nil
+	}
+
+	articles, err := n.articles.AllUnscored(ctx, time.Now().Add(-n.breaking.maxAge), breakingBatchSize)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	dryRun := n.isDryRun(n.channelID)
+
+	for _, article := range articles {
+		// In dry-run mode, the urgency is not stored, so the articles already checked are remembered instead.
+		if dryRun {
+			if n.dryRun.isScored(article.ID) {
+				continue
+			}
+
+			n.dryRun.markScored(article.ID)
+		}
+
+		article.Urgency = n.urgency(ctx, article)
+		article.Breaking = article.Urgency >= n.breaking.threshold
+
+		if article.Breaking {
+			posted, err := n.articles.CountBreaking(ctx, time.Now().Add(-time.Hour))
+			if err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			if posted >= n.breaking.maxPerHour {
+				log.Printf("[WARN] breaking news cap of %d per hour reached, article %d goes through the regular queue", n.breaking.maxPerHour, article.ID)
+				article.Breaking = false
+			}
+		}
+
+		if !dryRun {
+			if err := n.articles.UpdateUrgency(ctx, article); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+
+		if !article.Breaking {
+			continue
+		}
+
+		log.Printf("[INFO] posting breaking news article %d (urgency %.2f)", article.ID, article.Urgency)
+
+		if err := n.send(ctx, article); err != nil {
+			log.Printf("[ERROR] failed to send breaking news article %d: %v", article.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// urgency scores the article against the breaking-news rules. Keywords and sources give the highest
+// urgency; otherwise the urgency is scored by the scorer, if any. A failed scoring is logged and counts as not urgent.
+func (n *Notifier) urgency(ctx context.Context, article models.Article) float64 {
+	if _, ok := n.breaking.sources[article.SourceID]; ok {
+		return 1
+	}
+
+	text := article.Title + "\n" + article.Summary
+
+	for _, keyword := range n.breaking.keywords {
+		if keyword.MatchString(text) {
+			return 1
+		}
+	}
+
+	if n.breaking.scorer == nil {
+		return 0
+	}
+
+	urgency, err := n.breaking.scorer.Urgency(summary.WithArticleID(ctx, article.ID), article.Title+"\n\n"+article.Summary)
+	if err != nil {
+		log.Printf("[ERROR] failed to score urgency of article %d: %v", article.ID, err)
+		return 0
+	}
+
+	return urgency
+}
+
+// keywordPattern compiles the pattern matching the keyword as a whole word, case-insensitively,
+// so that e.g. "war" does not match "software". Unlike \b, the word boundaries work for any script.
+func keywordPattern(keyword string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)(?:^|[^\pL\pN])` + regexp.QuoteMeta(keyword) + `(?:$|[^\pL\pN])`)
+}
+
+// silent reports whether the post of the article must be sent without a notification sound.
+func (n *Notifier) silent(article models.Article) bool {
+	if article.Breaking && n.breaking != nil {
+		return !n.breaking.sound
+	}
+
+	return n.silentPosts
+}
+
+// pinBreaking pins the channel message of a breaking news article, if enabled.
+// A failure is logged, as the article has been posted anyway.
+func (n *Notifier) pinBreaking(article models.Article) {
+	if !article.Breaking || n.breaking == nil || !n.breaking.pin {
+		return
+	}
+
+	pin := tgbotapi.PinChatMessageConfig{
+		ChatID:              article.ChatID,
+		MessageID:           article.MessageID,
+		DisableNotification: !n.breaking.sound,
+	}
+
+	if _, err := n.bot.Request(pin); err != nil {
+		log.Printf("[ERROR] failed to pin breaking news article %d: %v", article.ID, err)
+	}
+}
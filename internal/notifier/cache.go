@@ -0,0 +1,80 @@
+package notifier
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+	"github.com/kirinyoku/echo-wire-bot/internal/summary"
+)
+
+// SummaryCache defines the interface for storing summaries for reuse.
+type SummaryCache interface {
+	// CachedSummary retrieves the summary of the text with the given hash, generated by the model
+	// with the given version of the prompt. An empty summary means there is no such summary.
+	CachedSummary(ctx context.Context, textHash, model, promptVersion string) (models.CachedSummary, error)
+	// StoreSummary stores the summary, replacing any summary with the same key.
+	StoreSummary(ctx context.Context, summary models.CachedSummary) error
+}
+
+// summarize generates the summary of the article text with the prompt. If the cache is enabled and reuse is set,
+// a summary of the same text by the same model and prompt is reused instead of generating a new one.
+// Summaries generated by the language model are stored in the cache, while those by the fallback
+// or a backup model are not, so that the primary language model is tried again next time.
+func (n *Notifier) summarize(
+	ctx context.Context,
+	article models.Article,
+	text string,
+	prompt summary.Prompt,
+	reuse bool,
+) (summary.Result, error) {
+	var (
+		model    = n.summarizer.Model()
+		version  = n.summarizer.PromptVersion(prompt)
+		textHash string
+	)
+
+	cacheable := n.summaryCache != nil && model != ""
+
+	if cacheable {
+		textHash = summary.Fingerprint(text)
+	}
+
+	if cacheable && reuse {
+		cached, err := n.summaryCache.CachedSummary(ctx, textHash, model, version)
+		if err != nil {
+			log.Printf("[WARN] failed to look up cached summary of article %d: %v", article.ID, err)
+		}
+
+		if cached.Summary != "" {
+			log.Printf("[INFO] reusing cached summary of article %d by %s", article.ID, model)
+			return summary.Result{Text: cached.Summary, Model: model, Cached: true, PromptVersion: version}, nil
+		}
+	}
+
+	result, err := n.summarizer.Summarize(ctx, text, prompt)
+	if err != nil {
+		return summary.Result{}, err
+	}
+
+	log.Printf(
+		"[INFO] summarized article %d with %s: %d prompt and %d completion tokens in %s",
+		article.ID, result.Model, result.PromptTokens, result.CompletionTokens, result.Latency.Round(time.Millisecond),
+	)
+
+	if cacheable && !result.Fallback && !result.Backup {
+		if err := n.summaryCache.StoreSummary(ctx, models.CachedSummary{
+			TextHash:         textHash,
+			Model:            model,
+			PromptVersion:    version,
+			Summary:          result.Text,
+			PromptTokens:     result.PromptTokens,
+			CompletionTokens: result.CompletionTokens,
+		}); err != nil {
+			log.Printf("[WARN] failed to cache summary of article %d: %v", article.ID, err)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,65 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+)
+
+const (
+	defaultMaxSendAttempts = 5
+	defaultRetryBackoff    = time.Minute
+	// maxRetryBackoff caps the delay between two attempts to post an article.
+	maxRetryBackoff = 6 * time.Hour
+	// claimLease is how long an article may stay claimed before its delivery is considered interrupted.
+	// Articles are claimed before their summary is generated, so the lease also covers the summary pipeline.
+	claimLease = time.Hour
+)
+
+// recordFailure stores a failed attempt to post the article. The article is
+// scheduled for another attempt with exponential backoff, or dead-lettered
+// once it has used up all attempts. The send error is returned for logging.
+func (n *Notifier) recordFailure(ctx context.Context, article models.Article, sendErr error) error {
+	const op = "notifier.recordFailure"
+
+	attempt := article.SendAttempts + 1
+
+	if attempt >= n.maxSendAttempts {
+		if err := n.articles.MarkAsDead(ctx, article, sendErr.Error()); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		log.Printf("[ERROR] article %d moved to dead letters after %d attempts", article.ID, attempt)
+
+		return fmt.Errorf("%s: article %d: %w", op, article.ID, sendErr)
+	}
+
+	retryAt := time.Now().Add(n.backoff(attempt))
+
+	if err := n.articles.MarkAsFailed(ctx, article, sendErr.Error(), retryAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return fmt.Errorf(
+		"%s: article %d, attempt %d, next attempt at %s: %w",
+		op,
+		article.ID,
+		attempt,
+		retryAt.UTC().Format(time.RFC3339),
+		sendErr,
+	)
+}
+
+// backoff returns the delay before the next attempt after the given number of failed attempts.
+func (n *Notifier) backoff(attempt int) time.Duration {
+	delay := n.retryBackoff
+
+	for i := 1; i < attempt && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxRetryBackoff)
+}
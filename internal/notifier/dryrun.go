@@ -0,0 +1,220 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/echo-wire-bot/internal/botkit"
+	"github.com/kirinyoku/echo-wire-bot/internal/botkit/markup"
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+	"github.com/kirinyoku/echo-wire-bot/internal/render"
+)
+
+// dryRunLookupLimit is the number of candidate articles considered in dry-run mode,
+// where already rendered articles stay in the queue.
+const dryRunLookupLimit = 50
+
+// DryRunMessage is a message that would have been sent to a destination chat.
+type DryRunMessage struct {
+	Time      time.Time `json:"time"`
+	ArticleID int64     `json:"article_id"`
+	ChatID    int64     `json:"chat_id"`
+	ParseMode string    `json:"parse_mode"`
+	Text      string    `json:"text"`
+	ImageURL  string    `json:"image_url,omitempty"`
+}
+
+// DryRunSink receives the messages rendered in dry-run mode.
+type DryRunSink interface {
+	Write(ctx context.Context, msg DryRunMessage) error
+}
+
+// dryRun holds the settings and the state of the dry-run mode.
+type dryRun struct {
+	global bool
+	chats  map[int64]struct{}
+	sink   DryRunSink
+
+	mu       sync.Mutex
+	rendered map[int64]struct{}
+	// scored holds the articles checked against the breaking-news rules, whose urgency is not stored.
+	scored map[int64]struct{}
+}
+
+// isRendered reports whether the article has already been rendered in dry-run mode.
+func (d *dryRun) isRendered(articleID int64) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	_, ok := d.rendered[articleID]
+
+	return ok
+}
+
+// markRendered remembers that the article has been rendered in dry-run mode.
+func (d *dryRun) markRendered(articleID int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.rendered[articleID] = struct{}{}
+}
+
+// isScored reports whether the article has already been checked against the breaking-news rules in dry-run mode.
+func (d *dryRun) isScored(articleID int64) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	_, ok := d.scored[articleID]
+
+	return ok
+}
+
+// markScored remembers that the article has been checked against the breaking-news rules in dry-run mode.
+func (d *dryRun) markScored(articleID int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.scored[articleID] = struct{}{}
+}
+
+// isDryRun reports whether messages to the chat must be diverted to the dry-run sink.
+func (n *Notifier) isDryRun(chatID int64) bool {
+	if n.dryRun == nil {
+		return false
+	}
+
+	_, ok := n.dryRun.chats[chatID]
+
+	return n.dryRun.global || ok
+}
+
+// dryRunDeliver renders the article as it would be sent to the chat and writes it to the dry-run sink.
+// The article is neither claimed nor marked as posted.
+func (n *Notifier) dryRunDeliver(ctx context.Context, chatID int64, tmpl *render.Template, article models.Article, summary string) error {
+	const op = "notifier.dryRunDeliver"
+
+	limit := render.MaxMessageLength
+	if article.ImageURL != "" {
+		limit = render.MaxCaptionLength
+	}
+
+	text, err := tmpl.ExecuteLimited(render.ArticleData(article, summary), limit)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := n.dryRun.sink.Write(ctx, DryRunMessage{
+		Time:      time.Now().UTC(),
+		ArticleID: article.ID,
+		ChatID:    chatID,
+		ParseMode: tmpl.ParseMode(),
+		Text:      text,
+		ImageURL:  article.ImageURL,
+	}); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n.dryRun.markRendered(article.ID)
+
+	return nil
+}
+
+// dryRunEdit renders the new text of the channel message of a posted article
+// and writes it to the dry-run sink instead of editing the message.
+func (n *Notifier) dryRunEdit(ctx context.Context, article models.Article, parseMode, text string) error {
+	const op = "notifier.dryRunEdit"
+
+	log.Printf("[DRY RUN] would edit message %d of article %d in chat %d", article.MessageID, article.ID, article.ChatID)
+
+	if err := n.dryRun.sink.Write(ctx, DryRunMessage{
+		Time:      time.Now().UTC(),
+		ArticleID: article.ID,
+		ChatID:    article.ChatID,
+		ParseMode: parseMode,
+		Text:      text,
+	}); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ChatSink writes dry-run messages to an admin chat, each preceded by a header naming the destination.
+type ChatSink struct {
+	bot    *tgbotapi.BotAPI
+	chatID int64
+}
+
+// NewChatSink creates a dry-run sink that sends messages to the given chat.
+func NewChatSink(bot *tgbotapi.BotAPI, chatID int64) *ChatSink {
+	return &ChatSink{bot: bot, chatID: chatID}
+}
+
+// Write sends the rendered message to the admin chat.
+func (s *ChatSink) Write(ctx context.Context, msg DryRunMessage) error {
+	escape := markup.Escaper(msg.ParseMode)
+
+	header := fmt.Sprintf("🧪 Dry run: article %d to chat %d", msg.ArticleID, msg.ChatID)
+	if msg.ImageURL != "" {
+		header += "\nImage: " + msg.ImageURL
+	}
+
+	reply := tgbotapi.NewMessage(s.chatID, escape(header)+"\n\n"+msg.Text)
+	reply.ParseMode = msg.ParseMode
+	reply.DisableWebPagePreview = true
+
+	_, err := botkit.Send(ctx, s.bot, reply)
+
+	return err
+}
+
+// FileSink appends dry-run messages to a file as JSON lines.
+type FileSink struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileSink creates a dry-run sink that writes messages to the file at path.
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+// Write appends the message to the file.
+func (s *FileSink) Write(_ context.Context, msg DryRunMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// LogSink writes dry-run messages to the standard logger.
+// It is used when neither an admin chat nor a log file is configured.
+type LogSink struct{}
+
+// Write logs the message.
+func (LogSink) Write(_ context.Context, msg DryRunMessage) error {
+	log.Printf("[DRY RUN] article %d to chat %d:\n%s", msg.ArticleID, msg.ChatID, msg.Text)
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+	"github.com/kirinyoku/echo-wire-bot/internal/summary"
+)
+
+const (
+	// enrichmentBatchSize is the maximum number of articles enriched per round.
+	enrichmentBatchSize = 10
+	// rankingWindow is the number of articles waiting to be posted that are ranked by relevance.
+	rankingWindow = 20
+	// neutralRelevance is the relevance assumed for articles that have not been enriched yet when ranking.
+	neutralRelevance = 0.5
+)
+
+// Enricher defines the interface for the structured analysis of articles.
+type Enricher interface {
+	// Enrich returns the summary, the topic tags, the relevance to the topic, the clickbait flag
+	// and the sentiment of the article. The summary is generated with the given prompt.
+	Enrich(ctx context.Context, title, text, topic string, summaryPrompt summary.Prompt) (models.Enrichment, error)
+}
+
+// EnrichmentRules configures which enriched articles are skipped.
+type EnrichmentRules struct {
+	// Topic is the description of the topic of the channel the relevance is scored against.
+	Topic string
+	// MinRelevance skips articles less relevant to the topic; 0 keeps all articles.
+	MinRelevance float64
+	// SkipClickbait skips articles flagged as clickbait.
+	SkipClickbait bool
+}
+
+// enrichment holds the settings of the enrichment of articles.
+type enrichment struct {
+	enricher    Enricher
+	rules       EnrichmentRules
+	interval    time.Duration
+	maxAttempts int
+	rank        bool
+}
+
+// enrichPendingLogged runs a single round of EnrichPending and logs its error.
+func (n *Notifier) enrichPendingLogged(ctx context.Context) {
+	if err := n.EnrichPending(ctx); err != nil && ctx.Err() == nil {
+		log.Printf("[ERROR] failed to enrich articles: %v", err)
+	}
+}
+
+// EnrichPending enriches articles waiting to be posted ahead of posting, so that they can be
+// ranked by relevance and the articles matching the skip rules are never posted.
+// Articles whose enrichment keeps failing are given up after the maximum attempts and are posted without it.
+// In dry-run mode nothing is enriched ahead, since the enrichment would not be stored.
+func (n *Notifier) EnrichPending(ctx context.Context) error {
+	if n.isDryRun(n.channelID) {
+		return nil
+	}
+
+	articles, err := n.articles.AllUnenriched(ctx, time.Now().Add(-n.expiry), n.enrichment.maxAttempts, enrichmentBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, article := range articles {
+		if _, _, err := n.enrich(ctx, article); err != nil {
+			if errors.Is(err, summary.ErrBudgetExceeded) || ctx.Err() != nil {
+				return err
+			}
+
+			log.Printf("[ERROR] failed to enrich article %d: %v", article.ID, err)
+
+			if err := n.articles.RecordEnrichmentFailure(ctx, article.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// enrich enriches the article, stores the enrichment and, if the article matches the skip rules,
+// marks it as filtered. It returns the enriched article and whether it has been filtered.
+func (n *Notifier) enrich(ctx context.Context, article models.Article) (models.Article, bool, error) {
+	ctx = summary.WithArticleID(ctx, article.ID)
+
+	text, _, err := n.articleText(ctx, article)
+	if err != nil {
+		// The title alone is enough to score the relevance.
+		text = article.Title
+	}
+
+	enriched, err := n.enrichment.enricher.Enrich(
+		ctx,
+		article.Title,
+		text,
+		n.enrichment.rules.Topic,
+		n.summaryPrompt(ctx, article, n.channelID),
+	)
+	if err != nil {
+		return article, false, err
+	}
+
+	enriched.EnrichedAt = time.Now()
+	article.Enrichment = enriched
+
+	reason := n.skipReason(article)
+
+	if n.isDryRun(n.channelID) {
+		if reason != "" {
+			log.Printf("[DRY RUN] would skip article %d: %s", article.ID, reason)
+			n.dryRun.markRendered(article.ID)
+		}
+
+		return article, reason != "", nil
+	}
+
+	if err := n.articles.UpdateEnrichment(ctx, article); err != nil {
+		return article, false, err
+	}
+
+	if reason == "" {
+		return article, false, nil
+	}
+
+	log.Printf("[INFO] skipping article %d: %s", article.ID, reason)
+
+	if err := n.articles.MarkAsFiltered(ctx, article.ID, reason); err != nil {
+		return article, false, err
+	}
+
+	return article, true, nil
+}
+
+// skipReason returns why the enriched article must be skipped, or an empty string if it must be posted.
+func (n *Notifier) skipReason(article models.Article) string {
+	rules := n.enrichment.rules
+
+	switch {
+	case rules.MinRelevance > 0 && article.Enrichment.Relevance < rules.MinRelevance:
+		return fmt.Sprintf("relevance %.2f is below %.2f", article.Enrichment.Relevance, rules.MinRelevance)
+	case rules.SkipClickbait && article.Enrichment.Clickbait:
+		return "flagged as clickbait"
+	default:
+		return ""
+	}
+}
+
+// mostRelevant returns the most relevant of the articles that share the priority of the first one,
+// i.e. approval and breaking news. Articles that have not been enriched have a neutral relevance,
+// and ties go to the first article, which is the newest.
+func mostRelevant(articles []models.Article) models.Article {
+	best := articles[0]
+
+	for _, article := range articles[1:] {
+		if article.Status != articles[0].Status || article.Breaking != articles[0].Breaking {
+			break
+		}
+
+		if relevance(article) > relevance(best) {
+			best = article
+		}
+	}
+
+	return best
+}
+
+// relevance returns the relevance of the article for ranking.
+func relevance(article models.Article) float64 {
+	if article.Enrichment.EnrichedAt.IsZero() {
+		return neutralRelevance
+	}
+
+	return article.Enrichment.Relevance
+}
@@ -0,0 +1,80 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/echo-wire-bot/internal/botkit"
+)
+
+// expiryReport holds the settings of the periodic report of expired articles.
+type expiryReport struct {
+	chatID   int64
+	interval time.Duration
+}
+
+// sendExpiryReportLogged sends the expiry report and logs its error.
+func (n *Notifier) sendExpiryReportLogged(ctx context.Context) {
+	if err := n.SendExpiryReport(ctx); err != nil && ctx.Err() == nil {
+		log.Printf("[ERROR] failed to send expiry report: %v", err)
+	}
+}
+
+// SendExpiryReport sends the number of articles per source that expired during the last report interval
+// to the admin chat. Nothing is sent if no articles expired.
+func (n *Notifier) SendExpiryReport(ctx context.Context) error {
+	const op = "notifier.SendExpiryReport"
+
+	if n.expiryReport == nil {
+		return nil
+	}
+
+	counts, err := n.articles.ExpiredCounts(ctx, time.Now().Add(-n.expiryReport.interval))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(counts) == 0 {
+		return nil
+	}
+
+	var (
+		b     strings.Builder
+		total int
+	)
+
+	for _, count := range counts {
+		total += count.Count
+	}
+
+	fmt.Fprintf(&b, "%d articles expired without being posted in the last %s:\n", total, shortDuration(n.expiryReport.interval))
+
+	for _, count := range counts {
+		fmt.Fprintf(&b, "\n• %s: %d", count.SourceName, count.Count)
+	}
+
+	if _, err := botkit.Send(ctx, n.bot, tgbotapi.NewMessage(n.expiryReport.chatID, b.String())); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// shortDuration formats the duration without trailing zero units, e.g. "24h" instead of "24h0m0s".
+func shortDuration(d time.Duration) string {
+	s := d.String()
+
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+
+	return s
+}
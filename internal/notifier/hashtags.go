@@ -0,0 +1,50 @@
+package notifier
+
+import (
+	"context"
+	"log"
+
+	"github.com/kirinyoku/echo-wire-bot/internal/hashtag"
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+)
+
+// EntityExtractor defines the interface for extracting named entities, such as people,
+// organizations and places, from the text of an article.
+type EntityExtractor interface {
+	// Entities returns the key named entities mentioned in the text.
+	Entities(ctx context.Context, text string) ([]string, error)
+}
+
+// hashtags holds the settings of the hashtags added to posts.
+type hashtags struct {
+	generator *hashtag.Generator
+	entities  EntityExtractor
+}
+
+// hashtagsFor builds the hashtags of the post from the source name, the feed categories, the topic tags
+// of the enrichment and, if enabled, the named entities of the title and the summary, in that order.
+// A failed entity extraction is logged and the other hashtags are kept.
+func (n *Notifier) hashtagsFor(ctx context.Context, article models.Article, summary string) []string {
+	if n.hashtags == nil {
+		return nil
+	}
+
+	candidates := append([]string{article.SourceName}, article.Categories...)
+	candidates = append(candidates, article.Enrichment.Tags...)
+
+	if n.hashtags.entities != nil {
+		title := article.Title
+		if article.PostTitle != "" {
+			title = article.PostTitle
+		}
+
+		entities, err := n.hashtags.entities.Entities(ctx, title+"\n\n"+summary)
+		if err != nil {
+			log.Printf("[ERROR] failed to extract entities of article %d: %v", article.ID, err)
+		}
+
+		candidates = append(candidates, entities...)
+	}
+
+	return n.hashtags.generator.Hashtags(candidates...)
+}
@@ -0,0 +1,276 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/echo-wire-bot/internal/botkit"
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+	"github.com/kirinyoku/echo-wire-bot/internal/render"
+)
+
+// ModerationCallbackPrefix is the prefix of the callback data of moderation buttons.
+const ModerationCallbackPrefix = "mod"
+
+// Actions of moderation buttons, sent as "<prefix>:<action>:<article id>" callback data.
+const (
+	ModerationActionApprove = "approve"
+	ModerationActionReject  = "reject"
+	ModerationActionEdit    = "edit"
+)
+
+// moderation holds the settings of the moderation mode.
+type moderation struct {
+	chatID      int64
+	autoApprove time.Duration
+}
+
+// submitForModeration sends a claimed article to the moderators chat with the moderation buttons.
+func (n *Notifier) submitForModeration(ctx context.Context, tmpl *render.Template, article models.Article, summary string) error {
+	if n.isDryRun(n.moderation.chatID) {
+		return n.dryRunDeliver(ctx, n.moderation.chatID, tmpl, article, summary)
+	}
+
+	ctx = context.WithoutCancel(ctx)
+
+	text, err := tmpl.ExecuteLimited(render.ArticleData(article, summary), render.MaxMessageLength)
+	if err != nil {
+		return n.recordFailure(ctx, article, err)
+	}
+
+	msg := tgbotapi.NewMessage(n.moderation.chatID, text)
+	msg.ParseMode = tmpl.ParseMode()
+	msg.ReplyMarkup = moderationKeyboard(article.ID)
+
+	sent, err := botkit.Send(ctx, n.bot, msg)
+	if err != nil {
+		return n.recordFailure(ctx, article, err)
+	}
+
+	article.ModerationChatID = sent.Chat.ID
+	article.ModerationMessageID = sent.MessageID
+	article.PostSummary = summary
+
+	return n.articles.MarkAsInModeration(ctx, article)
+}
+
+// Approve approves an article waiting for moderation and posts it to the channel.
+// If posting fails, the approved article is retried like any other post.
+// In dry-run mode, the article stays in moderation and is only rendered as it would be posted.
+func (n *Notifier) Approve(ctx context.Context, articleID int64) error {
+	const op = "notifier.Approve"
+
+	if n.isModerationDryRun() {
+		if err := n.dryRunApprove(ctx, articleID); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	}
+
+	article, err := n.decide(ctx, articleID, models.ArticleStatusApproved, "✅ Approved")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	tmpl, err := n.template(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := n.deliver(ctx, tmpl, article, article.PostSummary); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Reject rejects an article waiting for moderation, so that it is never posted.
+// In dry-run mode, the article stays in moderation.
+func (n *Notifier) Reject(ctx context.Context, articleID int64) error {
+	const op = "notifier.Reject"
+
+	if n.isModerationDryRun() {
+		log.Printf("[DRY RUN] would reject article %d", articleID)
+		return nil
+	}
+
+	if _, err := n.decide(ctx, articleID, models.ArticleStatusRejected, "❌ Rejected"); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// EditModerated replaces the summary of an article waiting for moderation
+// and updates its message in the moderators chat.
+// In dry-run mode, the new message is written to the dry-run sink and the summary is kept.
+func (n *Notifier) EditModerated(ctx context.Context, articleID int64, summary string) error {
+	const op = "notifier.EditModerated"
+
+	article, err := n.articles.ArticleByID(ctx, articleID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if article.Status != models.ArticleStatusModeration {
+		return fmt.Errorf("%s: article %d is not awaiting moderation", op, articleID)
+	}
+
+	// The summary is written by a moderator rather than generated with a prompt.
+	article.PromptVersion = ""
+
+	tmpl, err := n.template(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	text, err := tmpl.ExecuteLimited(render.ArticleData(article, summary), render.MaxMessageLength)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n.isModerationDryRun() {
+		article.ChatID = article.ModerationChatID
+		article.MessageID = article.ModerationMessageID
+
+		if err := n.dryRunEdit(ctx, article, tmpl.ParseMode(), text); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	}
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(
+		article.ModerationChatID,
+		article.ModerationMessageID,
+		text,
+		moderationKeyboard(article.ID),
+	)
+	edit.ParseMode = tmpl.ParseMode()
+
+	if _, err := botkit.Send(ctx, n.bot, edit); err != nil && !botkit.IsNotModified(err) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := n.articles.UpdatePostSummary(ctx, article, summary); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// decide moves an article from the moderation queue to the given status. It removes the moderation
+// buttons from the article's message and replies to it with the decision.
+func (n *Notifier) decide(ctx context.Context, articleID int64, status models.ArticleStatus, decision string) (models.Article, error) {
+	ok, err := n.articles.Transition(ctx, articleID, models.ArticleStatusModeration, status)
+	if err != nil {
+		return models.Article{}, err
+	}
+
+	if !ok {
+		return models.Article{}, fmt.Errorf("article %d is not awaiting moderation", articleID)
+	}
+
+	article, err := n.articles.ArticleByID(ctx, articleID)
+	if err != nil {
+		return models.Article{}, err
+	}
+
+	removeButtons := tgbotapi.NewEditMessageReplyMarkup(
+		article.ModerationChatID,
+		article.ModerationMessageID,
+		tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}},
+	)
+
+	if _, err := n.bot.Request(removeButtons); err != nil {
+		log.Printf("[WARN] failed to remove moderation buttons of article %d: %v", articleID, err)
+	}
+
+	reply := tgbotapi.NewMessage(article.ModerationChatID, decision)
+	reply.ReplyToMessageID = article.ModerationMessageID
+
+	if _, err := n.bot.Send(reply); err != nil {
+		log.Printf("[WARN] failed to reply with moderation decision for article %d: %v", articleID, err)
+	}
+
+	return article, nil
+}
+
+// autoApprove approves articles that have been waiting for moderation longer than the auto-approve timeout.
+func (n *Notifier) autoApprove(ctx context.Context) error {
+	if n.moderation == nil || n.moderation.autoApprove <= 0 {
+		return nil
+	}
+
+	articles, err := n.articles.AllInModeration(ctx, time.Now().Add(-n.moderation.autoApprove))
+	if err != nil {
+		return err
+	}
+
+	for _, article := range articles {
+		// In dry-run mode, approving leaves the article in moderation, so it is rendered only once.
+		if n.isModerationDryRun() && n.dryRun.isRendered(article.ID) {
+			continue
+		}
+
+		log.Printf("[INFO] auto-approving article %d after %s in moderation", article.ID, n.moderation.autoApprove)
+
+		if err := n.Approve(ctx, article.ID); err != nil {
+			log.Printf("[ERROR] failed to auto-approve article %d: %v", article.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// isModerationDryRun reports whether moderation decisions should be simulated, which is the case
+// when dry-run mode applies to the moderators chat or to the channel the approved articles are posted to.
+func (n *Notifier) isModerationDryRun() bool {
+	if n.moderation != nil && n.isDryRun(n.moderation.chatID) {
+		return true
+	}
+
+	return n.isDryRun(n.channelID)
+}
+
+// dryRunApprove renders an article waiting for moderation as it would be posted to the channel on approval.
+// The article is left in moderation.
+func (n *Notifier) dryRunApprove(ctx context.Context, articleID int64) error {
+	article, err := n.articles.ArticleByID(ctx, articleID)
+	if err != nil {
+		return err
+	}
+
+	if article.Status != models.ArticleStatusModeration {
+		return fmt.Errorf("article %d is not awaiting moderation", articleID)
+	}
+
+	tmpl, err := n.template(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DRY RUN] would approve article %d", articleID)
+
+	return n.dryRunDeliver(ctx, n.channelID, tmpl, article, article.PostSummary)
+}
+
+// moderationKeyboard builds the inline keyboard with the moderation buttons of an article.
+func moderationKeyboard(articleID int64) tgbotapi.InlineKeyboardMarkup {
+	data := func(action string) string {
+		return ModerationCallbackPrefix + ":" + action + ":" + strconv.FormatInt(articleID, 10)
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Approve", data(ModerationActionApprove)),
+			tgbotapi.NewInlineKeyboardButtonData("❌ Reject", data(ModerationActionReject)),
+			tgbotapi.NewInlineKeyboardButtonData("✏️ Edit summary", data(ModerationActionEdit)),
+		),
+	)
+}
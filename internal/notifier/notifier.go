@@ -3,17 +3,18 @@ package notifier
 import (
 	"context"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-shiori/go-readability"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
-	"github.com/kirinyoku/echo-wire-bot/internal/botkit/markup"
+	"github.com/kirinyoku/echo-wire-bot/internal/botkit"
 	"github.com/kirinyoku/echo-wire-bot/internal/models"
+	"github.com/kirinyoku/echo-wire-bot/internal/render"
+	"github.com/kirinyoku/echo-wire-bot/internal/summary"
 )
 
 // ArticleProvider defines the interface for working with articles.
@@ -21,123 +22,512 @@ type ArticlesProvider interface {
 	// AllNotPosted retrieves articles that have not been posted yet,
 	// filtered by a timestamp and limited by a specified number.
 	AllNotPosted(ctx context.Context, since time.Time, limit uint64) ([]models.Article, error)
-	// MarkAsPosted updates an article to indicate it has been posted.
+	// Claim marks an article as being sent. It returns false if the article must not be sent,
+	// because it has already been claimed or posted.
+	Claim(ctx context.Context, article models.Article) (bool, error)
+	// MarkAsPosted updates a claimed article to indicate it has been posted,
+	// storing the details of the channel message it was posted as.
 	MarkAsPosted(ctx context.Context, article models.Article) error
+	// UpdatePostSummary stores the summary shown in the channel message of a posted article.
+	UpdatePostSummary(ctx context.Context, article models.Article, summary string) error
+	// MarkAsRetracted marks a posted article whose channel message has been deleted.
+	MarkAsRetracted(ctx context.Context, article models.Article) error
+	// ReleaseStale dead-letters articles claimed for longer than the lease, as their delivery is unknown.
+	ReleaseStale(ctx context.Context, lease time.Duration) (int64, error)
+	// MarkAsFailed records a failed attempt to post an article and schedules the next attempt.
+	MarkAsFailed(ctx context.Context, article models.Article, reason string, retryAt time.Time) error
+	// MarkAsDead records the last failed attempt to post an article and moves it to the dead-letter state.
+	MarkAsDead(ctx context.Context, article models.Article, reason string) error
+	// ArticleByID retrieves an article by its ID.
+	ArticleByID(ctx context.Context, id int64) (models.Article, error)
+	// MarkAsInModeration moves a claimed article to the moderation queue.
+	MarkAsInModeration(ctx context.Context, article models.Article) error
+	// AllInModeration retrieves articles that have been waiting for moderation since before the given time.
+	AllInModeration(ctx context.Context, before time.Time) ([]models.Article, error)
+	// Transition changes the status of an article if it is still in the expected status.
+	Transition(ctx context.Context, id int64, from, to models.ArticleStatus) (bool, error)
+	// MarkAsExpired marks articles waiting to be posted that were published before the given time as expired.
+	MarkAsExpired(ctx context.Context, before time.Time) (int64, error)
+	// ExpiredCounts retrieves the number of articles per source that expired since the given time.
+	ExpiredCounts(ctx context.Context, since time.Time) ([]models.ExpiredCount, error)
+	// AllUnscored retrieves new articles that have not been checked against the breaking-news rules yet.
+	AllUnscored(ctx context.Context, since time.Time, limit uint64) ([]models.Article, error)
+	// UpdateUrgency stores the urgency score of an article and whether it is breaking news.
+	UpdateUrgency(ctx context.Context, article models.Article) error
+	// CountBreaking returns the number of articles marked as breaking news since the given time.
+	CountBreaking(ctx context.Context, since time.Time) (int, error)
+	// AllUnenriched retrieves articles waiting to be posted that were published since the given time
+	// and have not been enriched yet, leaving out those whose enrichment failed maxAttempts times.
+	AllUnenriched(ctx context.Context, since time.Time, maxAttempts int, limit uint64) ([]models.Article, error)
+	// RecordEnrichmentFailure records a failed enrichment of an article.
+	RecordEnrichmentFailure(ctx context.Context, id int64) error
+	// UpdateEnrichment stores the enrichment of an article.
+	UpdateEnrichment(ctx context.Context, article models.Article) error
+	// MarkAsFiltered marks an article waiting to be posted, or claimed for posting, as skipped by the enrichment rules.
+	MarkAsFiltered(ctx context.Context, id int64, reason string) error
+}
+
+// TemplateProvider defines the interface for retrieving admin-editable post templates.
+type TemplateProvider interface {
+	// Template retrieves a post template by its name.
+	// An empty template body means no template has been stored.
+	Template(ctx context.Context, name string) (models.PostTemplate, error)
+}
+
+// ContentProvider defines the interface for retrieving the content extracted from article pages.
+type ContentProvider interface {
+	// Content retrieves the extracted content of an article.
+	// A zero ExtractedAt means the content has not been extracted.
+	Content(ctx context.Context, articleID int64) (models.ArticleContent, error)
 }
 
 // Summarizer defines the interface for generating summaries of text content.
 type Summarizer interface {
-	// Summarize generates a summary for the provided text.
-	Summarize(text string) (string, error)
+	// Summarize generates a summary for the provided text, along with the metadata of its generation.
+	// An empty prompt means the default prompt of the summarizer.
+	Summarize(ctx context.Context, text string, prompt summary.Prompt) (summary.Result, error)
+	// Model returns the name of the model summaries are generated with, or an empty string if there is none.
+	Model() string
+	// PromptVersion returns the version of the prompts summaries are generated with when given the prompt.
+	PromptVersion(prompt summary.Prompt) string
+}
+
+// Translator defines the interface for translating the titles and summaries of articles.
+type Translator interface {
+	// Translate translates the text into the language given by its English name.
+	Translate(ctx context.Context, text, language string) (string, error)
 }
 
 // Notifier handles the process of selecting, summarizing, and sending articles
 // to a specified Telegram channel at regular intervals.
 type Notifier struct {
-	articles         ArticlesProvider
-	summarizer       Summarizer
-	bot              *tgbotapi.BotAPI
-	sendInterval     time.Duration
-	lookupTimeWindow time.Duration
-	channelID        int64
+	articles        ArticlesProvider
+	templates       TemplateProvider
+	contents        ContentProvider
+	summarizer      Summarizer
+	bot             *tgbotapi.BotAPI
+	sendInterval    time.Duration
+	expiry          time.Duration
+	channelID       int64
+	defaultTemplate string
+	parseMode       string
+	maxSendAttempts int
+	retryBackoff    time.Duration
+	moderation      *moderation
+	dryRun          *dryRun
+	translation     *translation
+	hashtags        *hashtags
+	telegraph       TelegraphPublisher
+	expiryReport    *expiryReport
+	breaking        *breaking
+	silentPosts     bool
+	summaryCache    SummaryCache
+	enrichment      *enrichment
+	prompts         *prompts
+	alertChatID     int64
 }
 
 // New initializes and returns a new Notifier instance.
+// Articles that are not posted within expiry after their publication are marked as expired.
 func New(
 	articleProvider ArticlesProvider,
+	templateProvider TemplateProvider,
+	contentProvider ContentProvider,
 	summarizer Summarizer,
 	bot *tgbotapi.BotAPI,
 	sendInterval time.Duration,
-	lookupTimeWindow time.Duration,
+	expiry time.Duration,
 	channelID int64,
+	defaultTemplate string,
+	parseMode string,
+	opts ...Option,
 ) *Notifier {
-	return &Notifier{
-		articles:         articleProvider,
-		summarizer:       summarizer,
-		bot:              bot,
-		sendInterval:     sendInterval,
-		lookupTimeWindow: lookupTimeWindow,
-		channelID:        channelID,
+	n := &Notifier{
+		articles:        articleProvider,
+		templates:       templateProvider,
+		contents:        contentProvider,
+		summarizer:      summarizer,
+		bot:             bot,
+		sendInterval:    sendInterval,
+		expiry:          expiry,
+		channelID:       channelID,
+		defaultTemplate: defaultTemplate,
+		parseMode:       parseMode,
+		maxSendAttempts: defaultMaxSendAttempts,
+		retryBackoff:    defaultRetryBackoff,
+	}
+
+	for _, opt := range opts {
+		opt(n)
 	}
+
+	return n
 }
 
-// Start begins the Notifier's routine to periodically send articles.
-// It stops when the context is canceled or an error occurs.
+// Start begins the Notifier's routine to periodically send articles and, if enabled,
+// to check new articles for breaking news and to send expiry reports.
+// Breaking news are checked in a separate goroutine, so that the fast lane never waits for a regular post.
+// Errors of individual rounds are logged and do not stop the routine;
+// it stops only when the context is canceled.
 func (n *Notifier) Start(ctx context.Context) error {
 	ticker := time.NewTicker(n.sendInterval)
 	defer ticker.Stop()
 
-	if err := n.SelectAndSendArticle(ctx); err != nil {
-		return err
+	var reports, enrichments <-chan time.Time
+
+	if n.expiryReport != nil {
+		reportTicker := time.NewTicker(n.expiryReport.interval)
+		defer reportTicker.Stop()
+
+		reports = reportTicker.C
+	}
+
+	if n.breaking != nil {
+		var wg sync.WaitGroup
+		defer wg.Wait()
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			n.runBreaking(ctx)
+		}()
+	}
+
+	if n.enrichment != nil {
+		enrichmentTicker := time.NewTicker(n.enrichment.interval)
+		defer enrichmentTicker.Stop()
+
+		enrichments = enrichmentTicker.C
 	}
 
+	n.selectAndSendLogged(ctx)
+
 	for {
 		select {
 		case <-ticker.C:
-			if err := n.SelectAndSendArticle(ctx); err != nil {
-				return err
-			}
+			n.selectAndSendLogged(ctx)
+		case <-enrichments:
+			n.enrichPendingLogged(ctx)
+		case <-reports:
+			n.sendExpiryReportLogged(ctx)
 		case <-ctx.Done():
 			return ctx.Err()
 		}
 	}
 }
 
+// selectAndSendLogged runs a single round of SelectAndSendArticle and logs its error.
+func (n *Notifier) selectAndSendLogged(ctx context.Context) {
+	if err := n.SelectAndSendArticle(ctx); err != nil && ctx.Err() == nil {
+		log.Printf("[ERROR] failed to send article: %v", err)
+	}
+}
+
 // SelectAndSendArticle selects the top article, generates a summary if needed,
 // sends the article to the Telegram channel, and marks it as posted.
+// In moderation mode, new articles are sent to moderators instead, and approved ones to the channel.
+// The article is claimed before sending so that it is never sent twice, even across restarts.
 func (n *Notifier) SelectAndSendArticle(ctx context.Context) error {
-	topOneArticles, err := n.articles.AllNotPosted(ctx, time.Now().Add(-n.lookupTimeWindow), 1)
+	// In dry-run mode, claims are neither released nor articles expired, so the queue is left as it is for the live run.
+	if !n.isDryRun(n.channelID) {
+		released, err := n.articles.ReleaseStale(ctx, claimLease)
+		if err != nil {
+			return err
+		}
+
+		if released > 0 {
+			log.Printf("[WARN] %d articles were interrupted while sending and moved to dead letters", released)
+		}
+
+		expired, err := n.articles.MarkAsExpired(ctx, time.Now().Add(-n.expiry))
+		if err != nil {
+			return err
+		}
+
+		if expired > 0 {
+			log.Printf("[WARN] %d articles were not posted within %s and expired", expired, shortDuration(n.expiry))
+		}
+	}
+
+	if err := n.autoApprove(ctx); err != nil {
+		return err
+	}
+
+	article, ok, err := n.selectArticle(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return nil
+	}
+
+	return n.send(ctx, article)
+}
+
+// send prepares the article and sends it to the channel, or to moderators in moderation mode.
+// The summary is generated and, if configured, translated into the language of the channel,
+// and the article gets its hashtags. The Telegraph page is published only when the article is delivered.
+// The article is claimed before it is prepared, so that the regular queue and the breaking-news fast lane
+// never pay for the summary of the same article twice.
+func (n *Notifier) send(ctx context.Context, article models.Article) error {
+	ctx = summary.WithArticleID(ctx, article.ID)
+
+	tmpl, err := n.template(ctx)
 	if err != nil {
 		return err
 	}
 
-	if len(topOneArticles) == 0 {
+	// Approved articles have been summarized before they were sent to moderators.
+	if article.Status == models.ArticleStatusApproved {
+		return n.deliver(ctx, tmpl, article, article.PostSummary)
+	}
+
+	destination := n.channelID
+	if n.moderation != nil {
+		destination = n.moderation.chatID
+	}
+
+	claimed, err := n.claim(ctx, article, destination)
+	if err != nil {
+		return err
+	}
+
+	if !claimed {
 		return nil
 	}
 
-	article := topOneArticles[0]
+	if n.enrichment != nil && article.Enrichment.EnrichedAt.IsZero() {
+		enriched, filtered, err := n.enrich(ctx, article)
+		if err != nil {
+			log.Printf("[ERROR] failed to enrich article %d: %v", article.ID, err)
+		}
+
+		if filtered {
+			return nil
+		}
+
+		article = enriched
+	}
+
+	article, summary := n.prepare(ctx, article)
 
-	summary, err := n.extractSummary(article)
+	// If the notifier is shutting down, the claim is released so that the article is prepared again
+	// on the next run rather than moved to dead letters as interrupted.
+	if ctx.Err() != nil {
+		return n.release(context.WithoutCancel(ctx), article, destination, ctx.Err())
+	}
+
+	if n.moderation != nil {
+		return n.submitForModeration(ctx, tmpl, article, summary)
+	}
+
+	return n.post(ctx, tmpl, article, summary)
+}
+
+// prepare generates the summary of the article, translates the article into the language of the channel
+// and builds its hashtags. It returns the article ready to be posted and the summary of the post.
+func (n *Notifier) prepare(ctx context.Context, article models.Article) (models.Article, string) {
+	result, image, err := n.extractSummary(ctx, article, n.channelID, true)
 	if err != nil {
 		log.Printf("[ERROR] failed to extract summary: %v", err)
 	}
 
-	if err := n.sendArticle(article, summary); err != nil {
-		return err
+	summary := result.Text
+	article.PromptVersion = result.PromptVersion
+
+	if article.ImageURL == "" {
+		article.ImageURL = image
 	}
 
-	return n.articles.MarkAsPosted(ctx, article)
+	article, summary = n.translate(ctx, article, summary, n.channelID)
+	article.Hashtags = n.hashtagsFor(ctx, article, summary)
+
+	return article, summary
 }
 
-var redundantNewLines = regexp.MustCompile(`\n{3,}`)
+// PostData returns the template fields of the article as it is, or would be, posted to the channel.
+// Articles that have already been prepared for posting keep their stored post. Other articles are prepared
+// the same way as when they are sent, with the summary, the translation and the hashtags, but nothing is stored.
+func (n *Notifier) PostData(ctx context.Context, article models.Article) render.Data {
+	switch article.Status {
+	case models.ArticleStatusModeration,
+		models.ArticleStatusApproved,
+		models.ArticleStatusPosted,
+		models.ArticleStatusRetracted:
+		return render.ArticleData(article, article.PostSummary)
+	}
 
-// extractSummary retrieves or generates a summary for the given article.
-func (n *Notifier) extractSummary(article models.Article) (string, error) {
-	var r io.Reader
+	ctx = summary.WithArticleID(ctx, article.ID)
+	article, summary := n.prepare(ctx, article)
 
-	if article.Summary != "" {
-		r = strings.NewReader(article.Summary)
-	} else {
-		resp, err := http.Get(article.Link)
-		if err != nil {
-			return "", err
+	return render.ArticleData(article, summary)
+}
+
+// selectArticle returns the top article to send next. In dry-run mode, articles
+// that have already been rendered are skipped, as they are never marked as posted.
+func (n *Notifier) selectArticle(ctx context.Context) (models.Article, bool, error) {
+	limit := uint64(1)
+	if n.enrichment != nil && n.enrichment.rank {
+		limit = rankingWindow
+	}
+
+	if n.dryRun != nil {
+		limit = max(limit, dryRunLookupLimit)
+	}
+
+	articles, err := n.articles.AllNotPosted(ctx, time.Now().Add(-n.expiry), limit)
+	if err != nil {
+		return models.Article{}, false, err
+	}
+
+	candidates := make([]models.Article, 0, len(articles))
+
+	for _, article := range articles {
+		if n.dryRun == nil || !n.dryRun.isRendered(article.ID) {
+			candidates = append(candidates, article)
 		}
-		defer resp.Body.Close()
+	}
+
+	if len(candidates) == 0 {
+		return models.Article{}, false, nil
+	}
+
+	if n.enrichment != nil && n.enrichment.rank {
+		return mostRelevant(candidates), true, nil
+	}
+
+	return candidates[0], true, nil
+}
+
+// claim claims the article for sending to the chat, so that it is never sent twice.
+// It reports whether the article has been claimed. In dry-run mode for the chat, the article is not claimed.
+func (n *Notifier) claim(ctx context.Context, article models.Article, chatID int64) (bool, error) {
+	if n.isDryRun(chatID) {
+		return true, nil
+	}
+
+	return n.articles.Claim(ctx, article)
+}
 
-		r = resp.Body
+// release returns a claimed article to the status it was loaded with and returns the given error.
+func (n *Notifier) release(ctx context.Context, article models.Article, chatID int64, cause error) error {
+	if n.isDryRun(chatID) {
+		return cause
 	}
 
-	doc, err := readability.FromReader(r, nil)
+	if _, err := n.articles.Transition(ctx, article.ID, models.ArticleStatusSending, article.Status); err != nil {
+		return fmt.Errorf("failed to release article %d: %w", article.ID, err)
+	}
+
+	return cause
+}
+
+// deliver claims the article and posts it to the channel.
+func (n *Notifier) deliver(ctx context.Context, tmpl *render.Template, article models.Article, summary string) error {
+	claimed, err := n.claim(ctx, article, n.channelID)
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	summary, err := n.summarizer.Summarize(cleanupText(doc.TextContent))
+	if !claimed {
+		return nil
+	}
+
+	return n.post(ctx, tmpl, article, summary)
+}
+
+// post publishes the Telegraph page of a claimed article, sends the article to the channel and marks it as posted.
+// The page is published only here, so that rejected articles are never published. A failed attempt is recorded so that the article is retried later.
+func (n *Notifier) post(ctx context.Context, tmpl *render.Template, article models.Article, summary string) error {
+	if n.isDryRun(n.channelID) {
+		return n.dryRunDeliver(ctx, n.channelID, tmpl, article, summary)
+	}
+
+	// The outcome of a claimed article must be stored even if the notifier is shutting down,
+	// otherwise it would stay claimed and end up in dead letters.
+	ctx = context.WithoutCancel(ctx)
+
+	article.TelegraphURL = n.telegraphPage(ctx, article)
+
+	msg, err := n.sendArticle(ctx, n.channelID, tmpl, article, summary)
 	if err != nil {
-		return "", err
+		return n.recordFailure(ctx, article, err)
 	}
 
-	return "\n\n" + summary, nil
+	article.ChatID = msg.Chat.ID
+	article.MessageID = msg.MessageID
+	article.PostSummary = summary
+	article.PostHasPhoto = len(msg.Photo) > 0
+
+	if err := n.articles.MarkAsPosted(ctx, article); err != nil {
+		return err
+	}
+
+	n.pinBreaking(article)
+
+	return nil
+}
+
+var redundantNewLines = regexp.MustCompile(`\n{3,}`)
+
+// extractSummary generates a summary for the given article from the content stored by the extractor.
+// If the page of the article has not been extracted, the summary from the feed is used instead.
+// It also returns the lead image found on the page (e.g. og:image), if any.
+// The article page is never downloaded here, so posting does not wait on the network.
+// If reuse is set, the summary of the enrichment or a cached summary of the same text is used when there is one.
+// The summary is generated with the prompt resolved for the article and the destination chat.
+func (n *Notifier) extractSummary(
+	ctx context.Context,
+	article models.Article,
+	chatID int64,
+	reuse bool,
+) (summary.Result, string, error) {
+	text, image, err := n.articleText(ctx, article)
+	if err != nil {
+		return summary.Result{}, image, err
+	}
+
+	if reuse && article.Enrichment.Summary != "" {
+		return summary.Result{
+			Text:          article.Enrichment.Summary,
+			PromptVersion: article.Enrichment.PromptVersion,
+		}, image, nil
+	}
+
+	result, err := n.summarize(ctx, article, text, n.summaryPrompt(ctx, article, chatID), reuse)
+	if err != nil {
+		return summary.Result{}, image, err
+	}
+
+	return result, image, nil
+}
+
+// articleText returns the text of the article extracted from its page or, if the page has not been
+// extracted, from the summary in the feed. It also returns the lead image found on the page, if any.
+func (n *Notifier) articleText(ctx context.Context, article models.Article) (string, string, error) {
+	content, err := n.contents.Content(ctx, article.ID)
+	if err != nil {
+		return "", "", err
+	}
+
+	text := content.Text
+
+	if text == "" && article.Summary != "" {
+		doc, err := readability.FromReader(strings.NewReader(article.Summary), nil)
+		if err != nil {
+			return "", content.ImageURL, err
+		}
+
+		text = cleanupText(doc.TextContent)
+	}
+
+	if strings.TrimSpace(text) == "" {
+		return "", content.ImageURL, fmt.Errorf("article %d has no text to summarize", article.ID)
+	}
+
+	return text, content.ImageURL, nil
 }
 
 // cleanupText removes redundant newlines from the provided text.
@@ -145,22 +535,48 @@ func cleanupText(text string) string {
 	return redundantNewLines.ReplaceAllString(text, "\n")
 }
 
-// sendArticle sends the article with its summary to the Telegram channel.
-func (n *Notifier) sendArticle(article models.Article, summary string) error {
-	const msgFormat = "*%s*%s\n\n%s"
+// sendArticle renders the article with its summary and sends it to the given chat.
+// Articles with a Telegraph page are sent as a text message with the link preview of the page, so that
+// Telegram offers Instant View. Other articles with a lead image are sent as a photo with a caption.
+// If the photo is rejected by Telegram, the article is sent as a text message instead.
+func (n *Notifier) sendArticle(
+	ctx context.Context,
+	chatID int64,
+	tmpl *render.Template,
+	article models.Article,
+	summary string,
+) (tgbotapi.Message, error) {
+	if article.ImageURL != "" && article.TelegraphURL == "" {
+		caption, err := tmpl.ExecuteLimited(render.ArticleData(article, summary), render.MaxCaptionLength)
+		if err != nil {
+			return tgbotapi.Message{}, err
+		}
+
+		photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileURL(article.ImageURL))
+		photo.Caption = caption
+		photo.ParseMode = tmpl.ParseMode()
+		photo.DisableNotification = n.silent(article)
 
-	msg := tgbotapi.NewMessage(n.channelID, fmt.Sprintf(
-		msgFormat,
-		markup.EscapeForMarkdown(article.Title),
-		markup.EscapeForMarkdown(summary),
-		markup.EscapeForMarkdown(article.Link),
-	))
-	msg.ParseMode = "MarkdownV2"
+		msg, err := botkit.Send(ctx, n.bot, photo)
+		if err == nil {
+			return msg, nil
+		}
 
-	_, err := n.bot.Send(msg)
+		log.Printf("[WARN] failed to send photo for article %d, falling back to text: %v", article.ID, err)
+	}
+
+	text, err := tmpl.ExecuteLimited(render.ArticleData(article, summary), render.MaxMessageLength)
 	if err != nil {
-		return err
+		return tgbotapi.Message{}, err
 	}
 
-	return nil
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = tmpl.ParseMode()
+	msg.DisableNotification = n.silent(article)
+
+	if article.TelegraphURL != "" {
+		return botkit.SendWithPreview(ctx, n.bot, msg, article.TelegraphURL)
+	}
+
+	return botkit.Send(ctx, n.bot, msg)
 }
@@ -0,0 +1,209 @@
+package notifier
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kirinyoku/echo-wire-bot/internal/hashtag"
+)
+
+// Option configures optional behavior of the Notifier.
+type Option func(*Notifier)
+
+// WithRetry sets how many times posting an article is attempted before it is
+// moved to the dead-letter state, and the delay before the first retry.
+// The delay doubles with every further attempt.
+func WithRetry(maxAttempts int, backoff time.Duration) Option {
+	return func(n *Notifier) {
+		if maxAttempts > 0 {
+			n.maxSendAttempts = maxAttempts
+		}
+
+		if backoff > 0 {
+			n.retryBackoff = backoff
+		}
+	}
+}
+
+// WithModeration enables the moderation mode. Instead of being posted to the channel, articles are sent
+// to the moderators chat with buttons to approve, reject or edit them. Articles that are not moderated
+// within autoApprove are approved automatically; zero disables automatic approval.
+// A zero chatID leaves moderation disabled.
+func WithModeration(chatID int64, autoApprove time.Duration) Option {
+	return func(n *Notifier) {
+		if chatID == 0 {
+			return
+		}
+
+		n.moderation = &moderation{
+			chatID:      chatID,
+			autoApprove: autoApprove,
+		}
+	}
+}
+
+// WithPrompts enables the prompt templates stored per source, per destination chat and globally.
+// When none of them applies, the default prompt is used. The variables {source}, {language} and {length}
+// of the templates are replaced with the name of the source, the language of the article
+// and maxLength, the maximum length of a summary in characters.
+func WithPrompts(provider PromptProvider, defaultPrompt string, maxLength int) Option {
+	return func(n *Notifier) {
+		n.prompts = &prompts{
+			provider:      provider,
+			defaultPrompt: defaultPrompt,
+			maxLength:     maxLength,
+		}
+	}
+}
+
+// WithDryRun enables the dry-run mode for all destinations when global is set, or only for the listed chats.
+// In dry-run mode, messages are rendered and written to the sink instead of the destination chat,
+// and articles are not marked as posted.
+func WithDryRun(global bool, chatIDs []int64, sink DryRunSink) Option {
+	return func(n *Notifier) {
+		if !global && len(chatIDs) == 0 {
+			return
+		}
+
+		chats := make(map[int64]struct{}, len(chatIDs))
+		for _, id := range chatIDs {
+			chats[id] = struct{}{}
+		}
+
+		n.dryRun = &dryRun{
+			global:   global,
+			chats:    chats,
+			sink:     sink,
+			rendered: make(map[int64]struct{}),
+			scored:   make(map[int64]struct{}),
+		}
+	}
+}
+
+// WithTranslation enables the translation of posts. Articles written in a language other than the target
+// language of the destination chat are posted with their title and summary translated. The target language
+// is looked up in chatLanguages by the chat ID, falling back to language; both are ISO 639-1 codes.
+func WithTranslation(translator Translator, language string, chatLanguages map[int64]string) Option {
+	return func(n *Notifier) {
+		if language == "" && len(chatLanguages) == 0 {
+			return
+		}
+
+		n.translation = &translation{
+			translator:    translator,
+			language:      language,
+			chatLanguages: chatLanguages,
+		}
+	}
+}
+
+// WithHashtags enables hashtags in posts. They are built by the generator from the source name,
+// the feed categories and, if entities is not nil, the named entities of the article.
+func WithHashtags(generator *hashtag.Generator, entities EntityExtractor) Option {
+	return func(n *Notifier) {
+		n.hashtags = &hashtags{
+			generator: generator,
+			entities:  entities,
+		}
+	}
+}
+
+// WithTelegraph enables publishing articles as Telegraph pages, linked from posts for Instant View.
+func WithTelegraph(publisher TelegraphPublisher) Option {
+	return func(n *Notifier) {
+		n.telegraph = publisher
+	}
+}
+
+// WithEnrichment enables the structured analysis of articles. Every interval, articles waiting to be posted
+// are enriched ahead of posting; articles that are still not enriched when they are posted are enriched then.
+// The summary of the enrichment is used for the post, its tags become hashtags, and articles matching
+// the rules are skipped. Articles whose enrichment failed maxAttempts times are no longer enriched ahead.
+// If rank is set, the most relevant article is posted first among those of the same priority, instead of the newest.
+func WithEnrichment(enricher Enricher, rules EnrichmentRules, interval time.Duration, maxAttempts int, rank bool) Option {
+	return func(n *Notifier) {
+		n.enrichment = &enrichment{
+			enricher:    enricher,
+			rules:       rules,
+			interval:    interval,
+			maxAttempts: max(maxAttempts, 1),
+			rank:        rank,
+		}
+	}
+}
+
+// WithSummaryCache enables reusing the summaries of texts that have already been summarized
+// by the same model with the same prompt, e.g. syndicated copies of an article.
+func WithSummaryCache(cache SummaryCache) Option {
+	return func(n *Notifier) {
+		n.summaryCache = cache
+	}
+}
+
+// WithExpiryReport enables the periodic report of articles that expired without being posted.
+// Every interval, the number of expired articles per source is sent to the chat.
+func WithExpiryReport(chatID int64, interval time.Duration) Option {
+	return func(n *Notifier) {
+		if chatID == 0 || interval <= 0 {
+			return
+		}
+
+		n.expiryReport = &expiryReport{
+			chatID:   chatID,
+			interval: interval,
+		}
+	}
+}
+
+// WithAlerts sets the admin chat receiving alerts, e.g. when the language model keeps failing.
+// Zero disables alerts.
+func WithAlerts(chatID int64) Option {
+	return func(n *Notifier) {
+		n.alertChatID = chatID
+	}
+}
+
+// WithBreaking enables the breaking-news fast lane. Every interval, articles published within maxAge
+// are checked against the rules, and breaking news are posted immediately, at most maxPerHour per hour.
+// Breaking news can be pinned in the channel, and are sent with a notification sound if sound is set.
+func WithBreaking(rules BreakingRules, interval, maxAge time.Duration, maxPerHour int, pin, sound bool) Option {
+	return func(n *Notifier) {
+		keywords := make([]*regexp.Regexp, 0, len(rules.Keywords))
+		for _, keyword := range rules.Keywords {
+			if keyword = strings.TrimSpace(keyword); keyword != "" {
+				keywords = append(keywords, keywordPattern(keyword))
+			}
+		}
+
+		sources := make(map[int64]struct{}, len(rules.SourceIDs))
+		for _, id := range rules.SourceIDs {
+			sources[id] = struct{}{}
+		}
+
+		// Without a valid threshold, only keywords and sources make breaking news.
+		threshold := rules.Threshold
+		if threshold <= 0 || threshold > 1 {
+			threshold = 1
+		}
+
+		n.breaking = &breaking{
+			keywords:   keywords,
+			sources:    sources,
+			scorer:     rules.Scorer,
+			threshold:  threshold,
+			interval:   interval,
+			maxAge:     maxAge,
+			maxPerHour: maxPerHour,
+			pin:        pin,
+			sound:      sound,
+		}
+	}
+}
+
+// WithSilentPosts sends regular posts without a notification sound.
+func WithSilentPosts(silent bool) Option {
+	return func(n *Notifier) {
+		n.silentPosts = silent
+	}
+}
@@ -0,0 +1,168 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/echo-wire-bot/internal/botkit"
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+	"github.com/kirinyoku/echo-wire-bot/internal/render"
+	"github.com/kirinyoku/echo-wire-bot/internal/summary"
+)
+
+// errNotPosted is returned when an operation on a channel post targets an article that has no post.
+var errNotPosted = errors.New("article has no channel post")
+
+// Retract deletes the channel message of a posted article and marks the article as retracted.
+// In dry-run mode for the chat of the post, nothing is deleted or stored.
+func (n *Notifier) Retract(ctx context.Context, article models.Article) error {
+	const op = "notifier.Retract"
+
+	if err := checkPosted(article); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n.isDryRun(article.ChatID) {
+		log.Printf("[DRY RUN] would delete message %d of article %d in chat %d", article.MessageID, article.ID, article.ChatID)
+		return nil
+	}
+
+	if _, err := n.bot.Request(tgbotapi.NewDeleteMessage(article.ChatID, article.MessageID)); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := n.articles.MarkAsRetracted(ctx, article); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Resummarize generates a new summary for a posted article and edits its channel message in place.
+func (n *Notifier) Resummarize(ctx context.Context, article models.Article) error {
+	const op = "notifier.Resummarize"
+
+	if err := checkPosted(article); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	ctx = summary.WithArticleID(ctx, article.ID)
+
+	// A new summary is generated rather than reusing a cached one.
+	result, _, err := n.extractSummary(ctx, article, article.ChatID, false)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	summary := result.Text
+	article.PromptVersion = result.PromptVersion
+
+	// The title of a translated article is kept, only the new summary is translated.
+	if target := n.translationTarget(article, summary, article.ChatID); target != "" {
+		if summary, err = n.translateSummary(ctx, summary, target); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := n.EditPost(ctx, article, summary); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// EditPost replaces the summary of a posted article and edits its channel message in place.
+// The message is rendered with the template in effect, so the title and the link are kept.
+// The prompt version of the article is stored along with the summary; it is empty for summaries written by hand.
+// In dry-run mode for the chat of the post, the new text is written to the dry-run sink and nothing is stored.
+func (n *Notifier) EditPost(ctx context.Context, article models.Article, summary string) error {
+	const op = "notifier.EditPost"
+
+	if err := checkPosted(article); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	tmpl, err := n.template(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n.isDryRun(article.ChatID) {
+		maxLength := render.MaxMessageLength
+		if article.PostHasPhoto {
+			maxLength = render.MaxCaptionLength
+		}
+
+		text, err := tmpl.ExecuteLimited(render.ArticleData(article, summary), maxLength)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err := n.dryRunEdit(ctx, article, tmpl.ParseMode(), text); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	}
+
+	var edit tgbotapi.Chattable
+
+	switch {
+	case article.PostHasPhoto:
+		caption, err := tmpl.ExecuteLimited(render.ArticleData(article, summary), render.MaxCaptionLength)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		editCaption := tgbotapi.NewEditMessageCaption(article.ChatID, article.MessageID, caption)
+		editCaption.ParseMode = tmpl.ParseMode()
+		edit = editCaption
+	case article.TelegraphURL != "":
+		text, err := tmpl.ExecuteLimited(render.ArticleData(article, summary), render.MaxMessageLength)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		editText := tgbotapi.NewEditMessageText(article.ChatID, article.MessageID, text)
+		editText.ParseMode = tmpl.ParseMode()
+
+		// The link preview of the Telegraph page is kept, even if it is not the first link of the text.
+		if _, err := botkit.EditWithPreview(ctx, n.bot, editText, article.TelegraphURL); err != nil && !botkit.IsNotModified(err) {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	default:
+		text, err := tmpl.ExecuteLimited(render.ArticleData(article, summary), render.MaxMessageLength)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		editText := tgbotapi.NewEditMessageText(article.ChatID, article.MessageID, text)
+		editText.ParseMode = tmpl.ParseMode()
+		edit = editText
+	}
+
+	// The summary is stored even if the message already has the new text, e.g. when an edit is repeated.
+	if edit != nil {
+		if _, err := botkit.Send(ctx, n.bot, edit); err != nil && !botkit.IsNotModified(err) {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := n.articles.UpdatePostSummary(ctx, article, summary); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// checkPosted returns an error if the article has no channel message to act on.
+func checkPosted(article models.Article) error {
+	if article.Status != models.ArticleStatusPosted || article.MessageID == 0 {
+		return fmt.Errorf("article %d: %w", article.ID, errNotPosted)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/kirinyoku/echo-wire-bot/internal/lang"
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+	"github.com/kirinyoku/echo-wire-bot/internal/summary"
+)
+
+// unknownLanguage replaces the {language} variable of prompts for articles whose language is not detected.
+const unknownLanguage = "the language of the article"
+
+// PromptProvider defines the interface for retrieving prompt templates from persistent storage.
+type PromptProvider interface {
+	// Prompt retrieves the latest version of the prompt of the given scope.
+	// An empty body means there is no prompt for the scope.
+	Prompt(ctx context.Context, scope models.PromptScope, scopeID int64) (models.Prompt, error)
+}
+
+// prompts holds the settings of the prompt templates articles are summarized with.
+type prompts struct {
+	provider      PromptProvider
+	defaultPrompt string
+	maxLength     int
+}
+
+// summaryPrompt returns the prompt the article posted to the chat is summarized with,
+// expecting the summary in the language of the article unless the prompt asks for another one.
+func (n *Notifier) summaryPrompt(ctx context.Context, article models.Article, chatID int64) summary.Prompt {
+	prompt := n.resolvePrompt(ctx, article, chatID)
+	prompt.Language = article.Language
+
+	return prompt
+}
+
+// resolvePrompt resolves the prompt template the article posted to the chat is summarized with
+// and renders it. The prompt of the source of the article takes precedence over the prompt of the chat,
+// which takes precedence over the global prompt. Without any of them, the default prompt is used.
+// Templates without the {language} variable may ask for any language, e.g. the language of the chat,
+// so their summaries are not checked against the language of the article.
+func (n *Notifier) resolvePrompt(ctx context.Context, article models.Article, chatID int64) summary.Prompt {
+	if n.prompts == nil {
+		return summary.Prompt{}
+	}
+
+	scopes := []struct {
+		scope models.PromptScope
+		id    int64
+	}{
+		{models.PromptScopeSource, article.SourceID},
+		{models.PromptScopeChat, chatID},
+		{models.PromptScopeGlobal, 0},
+	}
+
+	for _, s := range scopes {
+		prompt, err := n.prompts.provider.Prompt(ctx, s.scope, s.id)
+		if err != nil {
+			log.Printf("[WARN] failed to get %s prompt for article %d: %v", s.scope, article.ID, err)
+			continue
+		}
+
+		if prompt.Body != "" {
+			return summary.Prompt{
+				Text:        n.renderPrompt(prompt.Body, article),
+				Version:     promptVersion(prompt),
+				AnyLanguage: !strings.Contains(prompt.Body, "{language}"),
+			}
+		}
+	}
+
+	if n.prompts.defaultPrompt == "" {
+		return summary.Prompt{}
+	}
+
+	return summary.Prompt{
+		Text:        n.renderPrompt(n.prompts.defaultPrompt, article),
+		Version:     "config",
+		AnyLanguage: !strings.Contains(n.prompts.defaultPrompt, "{language}"),
+	}
+}
+
+// renderPrompt replaces the variables of the prompt template with the details of the article:
+// {source} with the name of its source, {language} with the name of its language
+// and {length} with the maximum length of a summary in characters.
+func (n *Notifier) renderPrompt(body string, article models.Article) string {
+	language := unknownLanguage
+	if article.Language != "" {
+		language = lang.Name(article.Language)
+	}
+
+	return strings.NewReplacer(
+		"{source}", article.SourceName,
+		"{language}", language,
+		"{length}", strconv.Itoa(n.prompts.maxLength),
+	).Replace(body)
+}
+
+// promptVersion returns the name of the version of the prompt, e.g. "global:v4" or "source:3:v2".
+func promptVersion(prompt models.Prompt) string {
+	if prompt.Scope == models.PromptScopeGlobal {
+		return fmt.Sprintf("%s:v%d", prompt.Scope, prompt.Version)
+	}
+
+	return fmt.Sprintf("%s:%d:v%d", prompt.Scope, prompt.ScopeID, prompt.Version)
+}
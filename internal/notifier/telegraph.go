@@ -0,0 +1,32 @@
+package notifier
+
+import (
+	"context"
+	"log"
+
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+)
+
+// TelegraphPublisher defines the interface for publishing articles as Telegraph pages.
+type TelegraphPublisher interface {
+	// Publish publishes the article and returns the URL of its page,
+	// or an empty URL if the article has nothing to publish.
+	Publish(ctx context.Context, article models.Article) (string, error)
+}
+
+// telegraphPage publishes the article as a Telegraph page and returns its URL.
+// It is called on delivery only, after moderation. Nothing is published in dry-run mode.
+// A failed publication is logged and the article is posted without a page.
+func (n *Notifier) telegraphPage(ctx context.Context, article models.Article) string {
+	if n.telegraph == nil || article.TelegraphURL != "" || n.isDryRun(n.channelID) {
+		return article.TelegraphURL
+	}
+
+	pageURL, err := n.telegraph.Publish(ctx, article)
+	if err != nil {
+		log.Printf("[ERROR] failed to publish article %d to Telegraph: %v", article.ID, err)
+		return ""
+	}
+
+	return pageURL
+}
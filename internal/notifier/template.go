@@ -0,0 +1,26 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kirinyoku/echo-wire-bot/internal/render"
+)
+
+// template returns the post template currently in effect.
+// The stored template takes precedence over the configured default.
+func (n *Notifier) template(ctx context.Context) (*render.Template, error) {
+	const op = "notifier.template"
+
+	stored, err := n.templates.Template(ctx, render.PostTemplateName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tmpl, err := render.New(render.TemplateText(stored, n.defaultTemplate, n.parseMode), n.parseMode)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tmpl, nil
+}
@@ -0,0 +1,97 @@
+package notifier
+
+import (
+	"context"
+	"log"
+
+	"github.com/kirinyoku/echo-wire-bot/internal/lang"
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+)
+
+// translation holds the settings of the translation of posts.
+type translation struct {
+	translator    Translator
+	language      string
+	chatLanguages map[int64]string
+}
+
+// targetLanguage returns the language posts to the chat must be written in, or an empty string
+// if posts to the chat are not translated.
+func (n *Notifier) targetLanguage(chatID int64) string {
+	if n.translation == nil {
+		return ""
+	}
+
+	if language, ok := n.translation.chatLanguages[chatID]; ok {
+		return language
+	}
+
+	return n.translation.language
+}
+
+// sourceLanguage returns the language of the article, detecting it from the title and the summary
+// if it was not detected when the article was fetched.
+func sourceLanguage(article models.Article, summary string) string {
+	if article.Language != "" {
+		return article.Language
+	}
+
+	return lang.Detect(article.Title + "\n" + summary)
+}
+
+// translationTarget returns the language the article must be translated into for the chat,
+// or an empty string if it needs no translation or its language is unknown.
+func (n *Notifier) translationTarget(article models.Article, summary string, chatID int64) string {
+	target := n.targetLanguage(chatID)
+	source := sourceLanguage(article, summary)
+
+	if target == "" || source == "" || lang.Same(source, target) {
+		return ""
+	}
+
+	return target
+}
+
+// translate translates the title and the summary of the article into the language of the chat,
+// if the article is written in a different language. The translated title is stored in PostTitle.
+// If the translation fails, the article and the summary are returned untranslated.
+func (n *Notifier) translate(ctx context.Context, article models.Article, summary string, chatID int64) (models.Article, string) {
+	target := n.translationTarget(article, summary, chatID)
+	if target == "" {
+		return article, summary
+	}
+
+	source := sourceLanguage(article, summary)
+
+	title, err := n.translation.translator.Translate(ctx, article.Title, lang.Name(target))
+	if err != nil {
+		log.Printf("[ERROR] failed to translate title of article %d: %v", article.ID, err)
+		return article, summary
+	}
+
+	if summary != "" {
+		if summary, err = n.translateSummary(ctx, summary, target); err != nil {
+			log.Printf("[ERROR] failed to translate summary of article %d: %v", article.ID, err)
+			return article, summary
+		}
+	}
+
+	article.Language = source
+	article.PostTitle = title
+
+	return article, summary
+}
+
+// translateSummary translates the summary into the target language, unless it is already written in it.
+func (n *Notifier) translateSummary(ctx context.Context, summary, target string) (string, error) {
+	if lang.Same(lang.Detect(summary), target) {
+		return summary, nil
+	}
+
+	translated, err := n.translation.translator.Translate(ctx, summary, lang.Name(target))
+	if err != nil {
+		return summary, err
+	}
+
+	return translated, nil
+}
@@ -0,0 +1,242 @@
+package page
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+const (
+	// maxRedirects is the maximum number of redirects followed for a single page.
+	maxRedirects = 5
+	// dialTimeout limits the time spent on establishing a connection.
+	dialTimeout = 10 * time.Second
+)
+
+var (
+	// ErrForbiddenAddress is returned when a page resolves or redirects to a private or local network address.
+	ErrForbiddenAddress = errors.New("address is not publicly routable")
+	// ErrDisallowedByRobots is returned when robots.txt of the site disallows fetching the page.
+	ErrDisallowedByRobots = errors.New("disallowed by robots.txt")
+	// ErrTooLarge is returned when the page exceeds the size limit.
+	ErrTooLarge = errors.New("page exceeds size limit")
+	// ErrUnsupportedContentType is returned when the page is not an HTML document.
+	ErrUnsupportedContentType = errors.New("unsupported content type")
+)
+
+// supportedContentTypes lists the media types that can be parsed by readability.
+var supportedContentTypes = map[string]struct{}{
+	"text/html":             {},
+	"application/xhtml+xml": {},
+}
+
+// Page is a downloaded web page.
+type Page struct {
+	// URL is the final URL of the page, after redirects.
+	URL  *url.URL
+	Body []byte
+}
+
+// Fetcher downloads article pages with a timeout, a size limit and a content type check.
+// It refuses to connect to private and local network addresses, including after redirects,
+// and optionally honors robots.txt.
+type Fetcher struct {
+	client    *http.Client
+	userAgent string
+	maxBytes  int64
+	robots    *robotsCache
+}
+
+// NewFetcher creates a new Fetcher.
+// Parameters:
+// - timeout: The time limit of a single page download, redirects included.
+// - maxBytes: The maximum size of a page body.
+// - userAgent: The User-Agent header sent with every request.
+// - respectRobots: Whether to check robots.txt before downloading a page.
+// - allowPrivate: Whether to allow private and local network addresses, e.g. for local testing.
+func NewFetcher(timeout time.Duration, maxBytes int64, userAgent string, respectRobots, allowPrivate bool) *Fetcher {
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	if !allowPrivate {
+		dialer.Control = forbidPrivateAddresses
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	f := &Fetcher{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= maxRedirects {
+					return fmt.Errorf("stopped after %d redirects", maxRedirects)
+				}
+
+				if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+					return fmt.Errorf("redirect to unsupported scheme %q", req.URL.Scheme)
+				}
+
+				return nil
+			},
+		},
+		userAgent: userAgent,
+		maxBytes:  maxBytes,
+	}
+
+	if respectRobots {
+		f.robots = newRobotsCache(f)
+	}
+
+	return f
+}
+
+// Fetch downloads the page at the given URL.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (Page, error) {
+	const op = "page.Fetcher.Fetch"
+
+	pageURL, err := url.Parse(rawURL)
+	if err != nil {
+		return Page{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if pageURL.Scheme != "http" && pageURL.Scheme != "https" {
+		return Page{}, fmt.Errorf("%s: unsupported scheme %q", op, pageURL.Scheme)
+	}
+
+	if f.robots != nil {
+		allowed, err := f.robots.allowed(ctx, pageURL)
+		if err != nil {
+			return Page{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if !allowed {
+			return Page{}, fmt.Errorf("%s: %s: %w", op, pageURL, ErrDisallowedByRobots)
+		}
+	}
+
+	resp, err := f.get(ctx, pageURL.String())
+	if err != nil {
+		return Page{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Page{}, fmt.Errorf("%s: unexpected status code: %d", op, resp.StatusCode)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return Page{}, fmt.Errorf("%s: %w: %q", op, ErrUnsupportedContentType, resp.Header.Get("Content-Type"))
+	}
+
+	if _, ok := supportedContentTypes[mediaType]; !ok {
+		return Page{}, fmt.Errorf("%s: %w: %q", op, ErrUnsupportedContentType, mediaType)
+	}
+
+	body, err := f.readLimited(resp)
+	if err != nil {
+		return Page{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return Page{URL: resp.Request.URL, Body: body}, nil
+}
+
+// get sends a GET request with the configured User-Agent.
+func (f *Fetcher) get(ctx context.Context, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", f.userAgent)
+
+	return f.client.Do(req)
+}
+
+// readLimited reads the response body, failing if it exceeds the size limit.
+func (f *Fetcher) readLimited(resp *http.Response) ([]byte, error) {
+	if resp.ContentLength > f.maxBytes {
+		return nil, ErrTooLarge
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(body)) > f.maxBytes {
+		return nil, ErrTooLarge
+	}
+
+	return body, nil
+}
+
+// forbidPrivateAddresses is a dialer control function that refuses connections to addresses
+// that are not publicly routable. It runs after name resolution, so it also covers DNS rebinding.
+func forbidPrivateAddresses(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublic(ip) {
+		return fmt.Errorf("%s: %w", host, ErrForbiddenAddress)
+	}
+
+	return nil
+}
+
+// reservedNetworks are the ranges that are not publicly routable, or that route to embedded IPv4 addresses,
+// which the methods of net.IP do not cover.
+var reservedNetworks = []*net.IPNet{
+	// "This network" (RFC 791), which reaches the local host on most systems.
+	mustParseCIDR("0.0.0.0/8"),
+	// Carrier-grade NAT (RFC 6598).
+	mustParseCIDR("100.64.0.0/10"),
+	// NAT64 (RFC 6052 and RFC 8215), which embed IPv4 addresses, including private ones.
+	mustParseCIDR("64:ff9b::/96"),
+	mustParseCIDR("64:ff9b:1::/48"),
+	// 6to4 (RFC 3056), which embeds IPv4 addresses as well.
+	mustParseCIDR("2002::/16"),
+}
+
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+
+	return network
+}
+
+// isPublic reports whether the IP address is publicly routable.
+func isPublic(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified() &&
+		!isReserved(ip)
+}
+
+// isReserved reports whether the IP address belongs to one of the reserved networks.
+func isReserved(ip net.IP) bool {
+	for _, network := range reservedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,230 @@
+package page
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+const (
+	// robotsTTL is how long the robots.txt of a site is cached.
+	robotsTTL = time.Hour
+	// maxRobotsBytes limits the size of robots.txt files.
+	maxRobotsBytes = 512 * 1024
+)
+
+// robotsRule is an Allow or Disallow rule of robots.txt.
+type robotsRule struct {
+	// path is the path pattern of the rule, whose length gives the precedence of the rule.
+	path    string
+	pattern *regexp.Regexp
+	allow   bool
+}
+
+// newRobotsRule compiles the path pattern of a rule. In the pattern, "*" matches any sequence
+// of characters and a trailing "$" anchors the pattern at the end of the path.
+func newRobotsRule(path string, allow bool) robotsRule {
+	pattern, anchored := strings.CutSuffix(path, "$")
+
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+
+	expr := "^" + strings.Join(parts, ".*")
+	if anchored {
+		expr += "$"
+	}
+
+	return robotsRule{path: path, pattern: regexp.MustCompile(expr), allow: allow}
+}
+
+// robotsRules holds the rules of robots.txt that apply to the fetcher.
+type robotsRules struct {
+	rules     []robotsRule
+	fetchedAt time.Time
+}
+
+// robotsCache downloads and caches robots.txt per site.
+type robotsCache struct {
+	fetcher *Fetcher
+
+	mu    sync.Mutex
+	sites map[string]robotsRules
+}
+
+func newRobotsCache(fetcher *Fetcher) *robotsCache {
+	return &robotsCache{
+		fetcher: fetcher,
+		sites:   make(map[string]robotsRules),
+	}
+}
+
+// allowed reports whether robots.txt of the site allows fetching the page.
+// Sites without a robots.txt, or with one that cannot be downloaded, allow everything.
+func (c *robotsCache) allowed(ctx context.Context, pageURL *url.URL) (bool, error) {
+	site := pageURL.Scheme + "://" + pageURL.Host
+
+	c.mu.Lock()
+	rules, ok := c.sites[site]
+	c.mu.Unlock()
+
+	if !ok || time.Since(rules.fetchedAt) > robotsTTL {
+		var err error
+
+		rules, err = c.load(ctx, site)
+		if err != nil {
+			return false, err
+		}
+
+		c.mu.Lock()
+		c.sites[site] = rules
+		c.mu.Unlock()
+	}
+
+	return rules.allowed(pageURL.RequestURI()), nil
+}
+
+// load downloads and parses robots.txt of the site.
+func (c *robotsCache) load(ctx context.Context, site string) (robotsRules, error) {
+	rules := robotsRules{fetchedAt: time.Now()}
+
+	resp, err := c.fetcher.get(ctx, site+"/robots.txt")
+	if err != nil {
+		if ctx.Err() != nil {
+			return robotsRules{}, ctx.Err()
+		}
+
+		return rules, nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return rules, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRobotsBytes))
+	if err != nil {
+		return rules, nil
+	}
+
+	rules.rules = parseRobots(body, c.fetcher.userAgent)
+
+	return rules, nil
+}
+
+// allowed applies the most specific matching rule to the path, including the query. Allow wins ties.
+func (r robotsRules) allowed(path string) bool {
+	if path == "" {
+		path = "/"
+	}
+
+	var (
+		best    = -1
+		allowed = true
+	)
+
+	for _, rule := range r.rules {
+		if !rule.pattern.MatchString(path) {
+			continue
+		}
+
+		if len(rule.path) > best || (len(rule.path) == best && rule.allow) {
+			best = len(rule.path)
+			allowed = rule.allow
+		}
+	}
+
+	return allowed
+}
+
+// parseRobots extracts the rules of robots.txt that apply to the user agent.
+// A group applies if its user agent is a case-insensitive prefix of the product token of the user agent,
+// e.g. "EchoWire" applies to "EchoWireBot/1.0". Such groups take precedence over the "*" group.
+func parseRobots(data []byte, userAgent string) []robotsRule {
+	var (
+		token       = productToken(userAgent)
+		specific    []robotsRule
+		wildcard    []robotsRule
+		hasSpecific bool
+		inAgents    bool
+		matches     bool
+		isWildcard  bool
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if !inAgents {
+				matches, isWildcard = false, false
+			}
+
+			inAgents = true
+			agent := productToken(value)
+
+			switch {
+			case agent == "*":
+				isWildcard = true
+			case agent != "" && strings.HasPrefix(token, agent):
+				matches = true
+				hasSpecific = true
+			}
+		case "allow", "disallow":
+			inAgents = false
+
+			if value == "" {
+				continue
+			}
+
+			rule := newRobotsRule(value, key == "allow")
+
+			if matches {
+				specific = append(specific, rule)
+			}
+
+			if isWildcard {
+				wildcard = append(wildcard, rule)
+			}
+		default:
+			inAgents = false
+		}
+	}
+
+	if hasSpecific {
+		return specific
+	}
+
+	return wildcard
+}
+
+// productToken returns the product name of a user agent in lower case, e.g. "echowirebot"
+// for "EchoWireBot/1.0 (+https://example.com)".
+func productToken(userAgent string) string {
+	token, _, _ := strings.Cut(strings.TrimSpace(userAgent), "/")
+
+	if i := strings.IndexFunc(token, unicode.IsSpace); i >= 0 {
+		token = token[:i]
+	}
+
+	return strings.ToLower(token)
+}
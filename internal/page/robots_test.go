@@ -0,0 +1,139 @@
+package page
+
+import "testing"
+
+func TestParseRobots(t *testing.T) {
+	const userAgent = "EchoWireBot/1.0 (+https://example.com/bot)"
+
+	tests := []struct {
+		name   string
+		robots string
+		path   string
+		want   bool
+	}{
+		{
+			name:   "no rules",
+			robots: "",
+			path:   "/news",
+			want:   true,
+		},
+		{
+			name:   "wildcard group",
+			robots: "User-agent: *\nDisallow: /private",
+			path:   "/private/page",
+			want:   false,
+		},
+		{
+			name:   "specific group takes precedence",
+			robots: "User-agent: *\nDisallow: /\n\nUser-agent: EchoWireBot\nDisallow: /private",
+			path:   "/news",
+			want:   true,
+		},
+		{
+			name:   "agent matched case-insensitively with version",
+			robots: "User-agent: *\nAllow: /\n\nUser-agent: echowirebot/2.0\nDisallow: /",
+			path:   "/news",
+			want:   false,
+		},
+		{
+			name:   "agent matched by prefix",
+			robots: "User-agent: EchoWire\nDisallow: /",
+			path:   "/news",
+			want:   false,
+		},
+		{
+			name:   "agent containing the token does not match",
+			robots: "User-agent: *\nAllow: /\n\nUser-agent: NotEchoWireBot\nDisallow: /",
+			path:   "/news",
+			want:   true,
+		},
+		{
+			name:   "shorter agent is not a prefix",
+			robots: "User-agent: *\nAllow: /\n\nUser-agent: Bot\nDisallow: /",
+			path:   "/news",
+			want:   true,
+		},
+		{
+			name:   "group with several agents",
+			robots: "User-agent: OtherBot\nUser-agent: EchoWireBot\nDisallow: /news",
+			path:   "/news/1",
+			want:   false,
+		},
+		{
+			name:   "longest rule wins",
+			robots: "User-agent: *\nDisallow: /news\nAllow: /news/public",
+			path:   "/news/public/1",
+			want:   true,
+		},
+		{
+			name:   "allow wins ties",
+			robots: "User-agent: *\nDisallow: /news\nAllow: /news",
+			path:   "/news",
+			want:   true,
+		},
+		{
+			name:   "wildcard in the middle",
+			robots: "User-agent: *\nDisallow: /*/print",
+			path:   "/news/print/1",
+			want:   false,
+		},
+		{
+			name:   "wildcard does not match elsewhere",
+			robots: "User-agent: *\nDisallow: /*/print",
+			path:   "/news/1",
+			want:   true,
+		},
+		{
+			name:   "trailing wildcard",
+			robots: "User-agent: *\nDisallow: /news*",
+			path:   "/newsletter",
+			want:   false,
+		},
+		{
+			name:   "end anchor",
+			robots: "User-agent: *\nDisallow: /*.pdf$",
+			path:   "/files/report.pdf",
+			want:   false,
+		},
+		{
+			name:   "end anchor does not match a longer path",
+			robots: "User-agent: *\nDisallow: /*.pdf$",
+			path:   "/files/report.pdf.html",
+			want:   true,
+		},
+		{
+			name:   "query",
+			robots: "User-agent: *\nDisallow: /*?print=",
+			path:   "/news/1?print=1",
+			want:   false,
+		},
+		{
+			name:   "special characters are literal",
+			robots: "User-agent: *\nDisallow: /a.b",
+			path:   "/axb",
+			want:   true,
+		},
+		{
+			name:   "empty disallow allows everything",
+			robots: "User-agent: *\nDisallow:",
+			path:   "/news",
+			want:   true,
+		},
+		{
+			name:   "comments",
+			robots: "# Robots\nUser-agent: * # everyone\nDisallow: /private # keep out",
+			path:   "/private",
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := robotsRules{rules: parseRobots([]byte(tt.robots), userAgent)}
+
+			if got := rules.allowed(tt.path); got != tt.want {
+				t.Errorf("allowed(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package render
+
+import (
+	"github.com/kirinyoku/echo-wire-bot/internal/lang"
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+)
+
+// PostTemplateName is the name under which the channel post template is stored.
+const PostTemplateName = "default"
+
+// TemplateText picks the text of the template in effect: the stored template,
+// the configured default or the built-in layout for the parse mode, in that order.
+func TemplateText(stored models.PostTemplate, defaultTemplate, parseMode string) string {
+	switch {
+	case stored.Body != "":
+		return stored.Body
+	case defaultTemplate != "":
+		return defaultTemplate
+	default:
+		return DefaultTemplate(parseMode)
+	}
+}
+
+// ArticleData converts the article and its summary into template fields.
+// The translated title is used if the article has been translated.
+func ArticleData(article models.Article, summary string) Data {
+	title := article.Title
+	if article.PostTitle != "" {
+		title = article.PostTitle
+	}
+
+	var language string
+	if article.Language != "" {
+		language = lang.Name(article.Language)
+	}
+
+	return Data{
+		Title:        title,
+		Summary:      summary,
+		Link:         article.Link,
+		SourceName:   article.SourceName,
+		Categories:   article.Categories,
+		Hashtags:     article.Hashtags,
+		TelegraphURL: article.TelegraphURL,
+		Language:     language,
+		Translated:   article.PostTitle != "",
+		PublishedAt:  article.PublishedAt,
+	}
+}
@@ -0,0 +1,146 @@
+package render
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+
+	"github.com/kirinyoku/echo-wire-bot/internal/botkit/markup"
+)
+
+const (
+	// MaxMessageLength is the maximum length of a Telegram text message.
+	MaxMessageLength = 4096
+	// MaxCaptionLength is the maximum length of a Telegram media caption.
+	MaxCaptionLength = 1024
+)
+
+// ellipsis is appended to text that had to be cut in the middle of a sentence.
+const ellipsis = "…"
+
+var htmlTags = regexp.MustCompile(`<[^>]*>`)
+
+// TextLength returns the length of plain text as counted by Telegram, in UTF-16 code units.
+func TextLength(text string) int {
+	return len(utf16.Encode([]rune(text)))
+}
+
+// VisibleLength returns the length of formatted text as counted by Telegram,
+// that is, after the markup of the parse mode has been parsed into entities.
+func VisibleLength(text, parseMode string) int {
+	switch parseMode {
+	case markup.ParseModeMarkdownV2:
+		return TextLength(stripMarkdown(text))
+	case markup.ParseModeHTML:
+		return TextLength(html.UnescapeString(htmlTags.ReplaceAllString(text, "")))
+	default:
+		return TextLength(text)
+	}
+}
+
+// stripMarkdown removes MarkdownV2 formatting characters, escapes and link URLs,
+// leaving only the text that is displayed to the user.
+func stripMarkdown(text string) string {
+	var (
+		b       strings.Builder
+		runes   = []rune(text)
+		newLine = true
+	)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case r == '\\' && i+1 < len(runes):
+			i++
+			b.WriteRune(runes[i])
+		case r == ']' && i+1 < len(runes) && runes[i+1] == '(':
+			// Skip the URL of an inline link up to the closing parenthesis.
+			for i += 2; i < len(runes) && runes[i] != ')'; i++ {
+				if runes[i] == '\\' {
+					i++
+				}
+			}
+		case r == '>' && newLine:
+		case strings.ContainsRune("*_~|`[", r):
+		default:
+			b.WriteRune(r)
+		}
+
+		newLine = r == '\n'
+	}
+
+	return b.String()
+}
+
+// truncateText shortens the text to at most limit UTF-16 code units.
+// The text is cut after the last complete sentence that fits. If not even
+// the first sentence fits, it is cut at a word boundary and an ellipsis is appended.
+func truncateText(text string, limit int) string {
+	if TextLength(text) <= limit {
+		return text
+	}
+
+	if limit <= TextLength(ellipsis) {
+		return ""
+	}
+
+	var (
+		b             strings.Builder
+		length        int
+		sentenceEnd   int
+		wordEnd       int
+		hardEnd       int
+		runes         = []rune(text)
+		sentenceLimit = limit
+		wordLimit     = limit - TextLength(ellipsis)
+	)
+
+	for i, r := range runes {
+		size := utf16.RuneLen(r)
+		if length+size > sentenceLimit {
+			break
+		}
+
+		b.WriteRune(r)
+		length += size
+
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		if isSentenceEnd(r) && (next == 0 || unicode.IsSpace(next)) {
+			sentenceEnd = b.Len()
+		}
+
+		if length <= wordLimit {
+			hardEnd = b.Len()
+
+			if next == 0 || unicode.IsSpace(next) {
+				wordEnd = b.Len()
+			}
+		}
+	}
+
+	truncated := b.String()
+
+	if sentenceEnd > 0 {
+		return strings.TrimSpace(truncated[:sentenceEnd])
+	}
+
+	if wordEnd == 0 {
+		wordEnd = hardEnd
+	}
+
+	return strings.TrimRightFunc(truncated[:wordEnd], func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}) + ellipsis
+}
+
+// isSentenceEnd reports whether the rune terminates a sentence.
+func isSentenceEnd(r rune) bool {
+	return strings.ContainsRune(".!?…。！？", r)
+}
@@ -0,0 +1,87 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/kirinyoku/echo-wire-bot/internal/botkit/markup"
+)
+
+func TestVisibleLength(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		parseMode string
+		want      int
+	}{
+		{"plain text", "Hello, world", markup.ParseModeText, 12},
+		{"surrogate pair", "Hi 😀", markup.ParseModeText, 5},
+		{"markdown formatting", "*Bold* and _italic_", markup.ParseModeMarkdownV2, 15},
+		{"markdown escapes", `Rates rose 0\.5% \- \*not\* bold`, markup.ParseModeMarkdownV2, 28},
+		{"markdown link", `See [the report](https://example\.com/a\)b)\.`, markup.ParseModeMarkdownV2, 15},
+		{"markdown quote", ">Quoted\n>text", markup.ParseModeMarkdownV2, 11},
+		{"html tags", `<b>Bold</b> and <a href="https://example.com">link</a>`, markup.ParseModeHTML, 13},
+		{"html entities", "<i>Tom &amp; Jerry &lt;3</i>", markup.ParseModeHTML, 14},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VisibleLength(tt.text, tt.parseMode); got != tt.want {
+				t.Errorf("VisibleLength(%q, %q) = %d, want %d", tt.text, tt.parseMode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"formatting", "*bold* _italic_ __underline__ ~strike~ ||spoiler|| `code`", "bold italic underline strike spoiler code"},
+		{"escapes", `1\+1 \= 2\. \\ \*`, `1+1 = 2. \ *`},
+		{"link", `[title](https://example\.com/\(a\))`, "title"},
+		{"quote at line start", ">one\ntwo > three", "one\ntwo > three"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripMarkdown(tt.text); got != tt.want {
+				t.Errorf("stripMarkdown(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  string
+	}{
+		{"fits", "Short text.", 11, "Short text."},
+		{"sentence boundary", "First sentence. Second sentence.", 20, "First sentence."},
+		{"last sentence that fits", "One. Two. Three.", 12, "One. Two."},
+		{"decimal point is not a sentence end", "It rose 5.5 percent today", 15, "It rose 5.5…"},
+		{"word boundary", "A sentence without an end", 12, "A sentence…"},
+		{"trailing punctuation", "Well, this is long", 8, "Well…"},
+		{"no word boundary", "Supercalifragilistic", 6, "Super…"},
+		{"cyrillic", "Очень длинное предложение", 10, "Очень…"},
+		{"surrogate pairs", "😀😀😀 smile", 6, "😀😀…"},
+		{"limit below ellipsis", "Anything", 1, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateText(tt.text, tt.limit)
+			if got != tt.want {
+				t.Errorf("truncateText(%q, %d) = %q, want %q", tt.text, tt.limit, got, tt.want)
+			}
+
+			if TextLength(got) > tt.limit {
+				t.Errorf("truncateText(%q, %d) is %d long", tt.text, tt.limit, TextLength(got))
+			}
+		})
+	}
+}
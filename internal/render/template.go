@@ -0,0 +1,170 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/kirinyoku/echo-wire-bot/internal/botkit/markup"
+)
+
+// Built-in post layouts used when no template is configured, one per parse mode,
+// since the markup of one parse mode is shown literally in the others.
+const (
+	defaultMarkdownTemplate = "*{{.Title}}*{{if .Summary}}\n\n{{.Summary}}{{end}}{{if .Translated}}\n\n_Translated from {{.Language}}_{{end}}{{if .TelegraphURL}}\n\n{{.TelegraphURL}}{{end}}\n\n{{.Link}}{{if .Hashtags}}\n\n{{join .Hashtags \" \"}}{{end}}"
+	defaultHTMLTemplate     = "<b>{{.Title}}</b>{{if .Summary}}\n\n{{.Summary}}{{end}}{{if .Translated}}\n\n<i>Translated from {{.Language}}</i>{{end}}{{if .TelegraphURL}}\n\n{{.TelegraphURL}}{{end}}\n\n{{.Link}}{{if .Hashtags}}\n\n{{join .Hashtags \" \"}}{{end}}"
+	defaultTextTemplate     = "{{.Title}}{{if .Summary}}\n\n{{.Summary}}{{end}}{{if .Translated}}\n\nTranslated from {{.Language}}{{end}}{{if .TelegraphURL}}\n\n{{.TelegraphURL}}{{end}}\n\n{{.Link}}{{if .Hashtags}}\n\n{{join .Hashtags \" \"}}{{end}}"
+)
+
+// DefaultTemplate returns the built-in post layout for the given parse mode.
+// Unknown parse modes are treated as plain text, as they are when escaping.
+func DefaultTemplate(parseMode string) string {
+	switch parseMode {
+	case markup.ParseModeMarkdownV2:
+		return defaultMarkdownTemplate
+	case markup.ParseModeHTML:
+		return defaultHTMLTemplate
+	default:
+		return defaultTextTemplate
+	}
+}
+
+// escapeFuncName is the name of the function appended to every template action.
+const escapeFuncName = "_escape"
+
+// Data holds the fields available to post templates.
+type Data struct {
+	Title      string
+	Summary    string
+	Link       string
+	SourceName string
+	Categories []string
+	// Hashtags are the hashtags of the post, with the leading '#'.
+	Hashtags []string
+	// TelegraphURL is the URL of the Telegraph page of the article, shown by Telegram in Instant View.
+	TelegraphURL string
+	// Language is the name of the language the article is written in, if detected.
+	Language string
+	// Translated reports whether the title and the summary have been translated from Language.
+	Translated  bool
+	PublishedAt time.Time
+}
+
+// Template is a post template bound to a Telegram parse mode.
+// The output of every template action is escaped for that parse mode,
+// so field values can never break the message markup.
+type Template struct {
+	tmpl      *template.Template
+	parseMode string
+}
+
+// New parses the template text and prepares it for the given parse mode.
+func New(text, parseMode string) (*Template, error) {
+	const op = "render.New"
+
+	tmpl, err := template.New("post").
+		Funcs(template.FuncMap{
+			escapeFuncName: markup.Escaper(parseMode),
+			"join":         strings.Join,
+			"date":         func(layout string, t time.Time) string { return t.Format(layout) },
+		}).
+		Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			escapeList(t.Tree, t.Tree.Root)
+		}
+	}
+
+	return &Template{tmpl: tmpl, parseMode: parseMode}, nil
+}
+
+// ParseMode returns the Telegram parse mode of the rendered text.
+func (t *Template) ParseMode() string {
+	return t.parseMode
+}
+
+// Execute renders the template with the given data.
+func (t *Template) Execute(data Data) (string, error) {
+	const op = "render.Template.Execute"
+
+	var buf bytes.Buffer
+
+	if err := t.tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return buf.String(), nil
+}
+
+// ExecuteLimited renders the template so that its visible length does not exceed limit.
+// The summary is shortened first, at a sentence boundary where possible, then the title.
+// Other fields, including the link, are always kept intact. Fields are shortened before
+// escaping, so the escapes of the parse mode are never cut in half.
+func (t *Template) ExecuteLimited(data Data, limit int) (string, error) {
+	const op = "render.Template.ExecuteLimited"
+
+	text, err := t.Execute(data)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		overflow := VisibleLength(text, t.parseMode) - limit
+		if overflow <= 0 {
+			return text, nil
+		}
+
+		switch {
+		case data.Summary != "":
+			data.Summary = truncateText(data.Summary, TextLength(data.Summary)-overflow)
+		case data.Title != "":
+			data.Title = truncateText(data.Title, TextLength(data.Title)-overflow)
+		default:
+			return "", fmt.Errorf("%s: rendered text exceeds %d characters by %d", op, limit, overflow)
+		}
+
+		if text, err = t.Execute(data); err != nil {
+			return "", err
+		}
+	}
+}
+
+// escapeList walks the template tree and appends the escape function to every action that produces output.
+func escapeList(tree *parse.Tree, list *parse.ListNode) {
+	if list == nil {
+		return
+	}
+
+	for _, node := range list.Nodes {
+		switch n := node.(type) {
+		case *parse.ActionNode:
+			if len(n.Pipe.Decl) > 0 {
+				continue
+			}
+
+			n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+				NodeType: parse.NodeCommand,
+				Pos:      n.Pos,
+				Args:     []parse.Node{parse.NewIdentifier(escapeFuncName).SetTree(tree).SetPos(n.Pos)},
+			})
+		case *parse.IfNode:
+			escapeList(tree, n.List)
+			escapeList(tree, n.ElseList)
+		case *parse.RangeNode:
+			escapeList(tree, n.List)
+			escapeList(tree, n.ElseList)
+		case *parse.WithNode:
+			escapeList(tree, n.List)
+			escapeList(tree, n.ElseList)
+		case *parse.ListNode:
+			escapeList(tree, n)
+		}
+	}
+}
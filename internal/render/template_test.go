@@ -0,0 +1,147 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kirinyoku/echo-wire-bot/internal/botkit/markup"
+)
+
+func TestTemplateExecuteLimited(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		parseMode string
+		data      Data
+		limit     int
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "fits",
+			text:      "*{{.Title}}*\n\n{{.Summary}}",
+			parseMode: markup.ParseModeMarkdownV2,
+			data:      Data{Title: "Rates", Summary: "Rates rose 0.5%."},
+			limit:     MaxMessageLength,
+			want:      "*Rates*\n\nRates rose 0\\.5%\\.",
+		},
+		{
+			name:      "markdown summary cut at a sentence boundary",
+			text:      "*{{.Title}}*\n\n{{.Summary}}",
+			parseMode: markup.ParseModeMarkdownV2,
+			data:      Data{Title: "Rates", Summary: "Rates rose 0.5%. Markets fell."},
+			limit:     25,
+			want:      "*Rates*\n\nRates rose 0\\.5%\\.",
+		},
+		{
+			name:      "html summary cut at a sentence boundary",
+			text:      "<b>{{.Title}}</b>\n{{.Summary}}",
+			parseMode: markup.ParseModeHTML,
+			data:      Data{Title: "Tom & Jerry", Summary: "A & B. C & D."},
+			limit:     18,
+			want:      "<b>Tom &amp; Jerry</b>\nA &amp; B.",
+		},
+		{
+			name:      "summary cut at a word boundary",
+			text:      "{{.Title}}\n{{.Summary}}",
+			parseMode: markup.ParseModeText,
+			data:      Data{Title: "News", Summary: "A sentence without an end"},
+			limit:     17,
+			want:      "News\nA sentence…",
+		},
+		{
+			name:      "title longer than the limit",
+			text:      "*{{.Title}}*{{if .Summary}}\n\n{{.Summary}}{{end}}\n\n{{.Link}}",
+			parseMode: markup.ParseModeMarkdownV2,
+			data:      Data{Title: "An unusually long headline about nothing", Summary: "Some summary.", Link: "https://example.com/a"},
+			limit:     40,
+			want:      "*An unusually…*\n\nhttps://example\\.com/a",
+		},
+		{
+			name:      "link longer than the limit",
+			text:      "{{.Title}}\n{{.Link}}",
+			parseMode: markup.ParseModeText,
+			data:      Data{Title: "News", Link: "https://example.com/a"},
+			limit:     10,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := New(tt.text, tt.parseMode)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			got, err := tmpl.ExecuteLimited(tt.data, tt.limit)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExecuteLimited() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ExecuteLimited() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateExecuteLimitedCaption(t *testing.T) {
+	tmpl, err := New(DefaultTemplate(markup.ParseModeMarkdownV2), markup.ParseModeMarkdownV2)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	data := Data{
+		Title:    "Rates (again) rise",
+		Summary:  strings.Repeat("Rates rose by 0.5% - again. ", 100),
+		Link:     "https://example.com/rates",
+		Hashtags: []string{"#Rates", "#Markets"},
+	}
+
+	got, err := tmpl.ExecuteLimited(data, MaxCaptionLength)
+	if err != nil {
+		t.Fatalf("ExecuteLimited() error = %v", err)
+	}
+
+	if length := VisibleLength(got, markup.ParseModeMarkdownV2); length > MaxCaptionLength {
+		t.Errorf("ExecuteLimited() is %d long, want at most %d", length, MaxCaptionLength)
+	}
+
+	for _, want := range []string{`*Rates \(again\) rise*`, `again\.` + "\n\n" + `https://example\.com/rates`, `\#Rates \#Markets`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ExecuteLimited() = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestDefaultTemplate(t *testing.T) {
+	data := Data{Title: "Tom & Jerry", Summary: "A summary.", Link: "https://example.com", Language: "Russian", Translated: true}
+
+	tests := []struct {
+		parseMode string
+		want      string
+	}{
+		{markup.ParseModeMarkdownV2, "*Tom & Jerry*\n\nA summary\\.\n\n_Translated from Russian_\n\nhttps://example\\.com"},
+		{markup.ParseModeHTML, "<b>Tom &amp; Jerry</b>\n\nA summary.\n\n<i>Translated from Russian</i>\n\nhttps://example.com"},
+		{markup.ParseModeText, "Tom & Jerry\n\nA summary.\n\nTranslated from Russian\n\nhttps://example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.parseMode, func(t *testing.T) {
+			tmpl, err := New(DefaultTemplate(tt.parseMode), tt.parseMode)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			got, err := tmpl.Execute(data)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Execute() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
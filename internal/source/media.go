@@ -0,0 +1,109 @@
+package source
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+)
+
+// mediaNamespace is the XML namespace of the Media RSS extension.
+const mediaNamespace = "http://search.yahoo.com/mrss/"
+
+// mediaFeed captures the Media RSS elements of RSS 2.0 and Atom feeds,
+// which the rss package does not expose.
+type mediaFeed struct {
+	Items   []mediaItem `xml:"channel>item"`
+	Entries []mediaItem `xml:"entry"`
+}
+
+type mediaItem struct {
+	Links      []mediaLink    `xml:"link"`
+	Contents   []mediaContent `xml:"http://search.yahoo.com/mrss/ content"`
+	Groups     []mediaGroup   `xml:"http://search.yahoo.com/mrss/ group"`
+	Thumbnails []mediaContent `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+}
+
+type mediaLink struct {
+	Href     string `xml:"href,attr"`
+	Rel      string `xml:"rel,attr"`
+	Chardata string `xml:",chardata"`
+}
+
+type mediaGroup struct {
+	Contents   []mediaContent `xml:"http://search.yahoo.com/mrss/ content"`
+	Thumbnails []mediaContent `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+}
+
+type mediaContent struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Medium string `xml:"medium,attr"`
+}
+
+// parseMediaImages extracts the first image of every feed item from the Media RSS extension.
+// The result is keyed by the item link. Feeds without media elements produce an empty map.
+func parseMediaImages(data []byte) map[string]string {
+	images := make(map[string]string)
+
+	if !bytes.Contains(data, []byte(mediaNamespace)) {
+		return images
+	}
+
+	var feed mediaFeed
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		return images
+	}
+
+	for _, item := range append(feed.Items, feed.Entries...) {
+		link := item.link()
+		if link == "" {
+			continue
+		}
+
+		if image := item.image(); image != "" {
+			images[link] = image
+		}
+	}
+
+	return images
+}
+
+// link returns the item link, preferring the alternate link of Atom entries.
+func (i mediaItem) link() string {
+	for _, l := range i.Links {
+		if l.Href != "" && (l.Rel == "" || l.Rel == "alternate") {
+			return l.Href
+		}
+
+		if text := strings.TrimSpace(l.Chardata); text != "" {
+			return text
+		}
+	}
+
+	return ""
+}
+
+// image returns the first image among media contents, groups and thumbnails.
+func (i mediaItem) image() string {
+	contents := i.Contents
+	thumbnails := i.Thumbnails
+
+	for _, g := range i.Groups {
+		contents = append(contents, g.Contents...)
+		thumbnails = append(thumbnails, g.Thumbnails...)
+	}
+
+	for _, c := range contents {
+		if c.URL != "" && (c.Medium == "image" || strings.HasPrefix(c.Type, "image/")) {
+			return c.URL
+		}
+	}
+
+	for _, t := range thumbnails {
+		if t.URL != "" {
+			return t.URL
+		}
+	}
+
+	return ""
+}
@@ -2,12 +2,30 @@ package source
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/SlyMarbo/rss"
 	"github.com/kirinyoku/echo-wire-bot/internal/models"
 )
 
+const (
+	// feedTimeout limits the time spent on downloading a single feed.
+	feedTimeout = 30 * time.Second
+	// maxFeedBytes is the maximum size of a feed, larger feeds are skipped.
+	maxFeedBytes = 10 << 20
+)
+
+// errFeedTooLarge is returned when the feed exceeds maxFeedBytes.
+var errFeedTooLarge = errors.New("feed exceeds size limit")
+
+// feedClient downloads feeds. Unlike http.DefaultClient, it does not wait on a hanging server forever.
+var feedClient = &http.Client{Timeout: feedTimeout}
+
 // RSSSource represents a specific RSS feed source.
 type RSSSource struct {
 	URL        string
@@ -29,7 +47,7 @@ func NewRSSSource(m models.Source) RSSSource {
 func (s RSSSource) Fetch(ctx context.Context) ([]models.Item, error) {
 	const op = "source.RSSSource.Fetch"
 
-	feed, err := s.loadFeed(ctx, s.URL)
+	feed, images, err := s.loadFeed(ctx, s.URL)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -43,6 +61,7 @@ func (s RSSSource) Fetch(ctx context.Context) ([]models.Item, error) {
 			Link:       item.Link,
 			Date:       item.Date,
 			Summary:    item.Summary,
+			ImageURL:   itemImage(item, images),
 			SourceName: s.SourceName,
 		})
 	}
@@ -50,31 +69,62 @@ func (s RSSSource) Fetch(ctx context.Context) ([]models.Item, error) {
 	return items, nil
 }
 
-// loadFeed asynchronously fetches the RSS feed and returns it.
-func (s RSSSource) loadFeed(ctx context.Context, url string) (*rss.Feed, error) {
-	var (
-		feedCh = make(chan *rss.Feed)
-		errCh  = make(chan error)
-	)
-
-	go func() {
-		feed, err := rss.Fetch(url)
-		if err != nil {
-			errCh <- <-errCh
-			return
+// loadFeed downloads the feed and parses both the feed itself and the media images of its items.
+func (s RSSSource) loadFeed(ctx context.Context, url string) (*rss.Feed, map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := feedClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	if resp.ContentLength > maxFeedBytes {
+		return nil, nil, errFeedTooLarge
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedBytes+1))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(data) > maxFeedBytes {
+		return nil, nil, errFeedTooLarge
+	}
+
+	feed, err := rss.Parse(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return feed, parseMediaImages(data), nil
+}
+
+// itemImage picks the lead image of a feed item.
+// Image enclosures take precedence, followed by media:content/media:thumbnail and the item image.
+func itemImage(item *rss.Item, images map[string]string) string {
+	for _, enclosure := range item.Enclosures {
+		if enclosure != nil && enclosure.URL != "" && strings.HasPrefix(enclosure.Type, "image/") {
+			return enclosure.URL
 		}
+	}
 
-		feedCh <- feed
-	}()
+	if image, ok := images[item.Link]; ok {
+		return image
+	}
 
-	select {
-	case <-ctx.Done():
-		return nil, ctx.Err()
-	case err := <-errCh:
-		return nil, err
-	case feed := <-feedCh:
-		return feed, nil
+	if item.Image != nil && item.Image.URL != "" {
+		return item.Image.URL
 	}
+
+	return ""
 }
 
 func (s RSSSource) ID() int64 {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"github.com/kirinyoku/echo-wire-bot/internal/models"
+	"github.com/lib/pq"
 )
 
 // ArticlePostgresStorage provides methods to interact with the articles table in a PostgreSQL database.
@@ -33,13 +34,16 @@ func (s *ArticlePostgresStorage) Store(ctx context.Context, article models.Artic
 
 	if _, err := conn.ExecContext(
 		ctx,
-		`INSERT INTO articles (source_id, title, link, summary, published_at)
-	    				VALUES ($1, $2, $3, $4, $5)
+		`INSERT INTO articles (source_id, title, link, summary, image_url, categories, language, published_at)
+	    				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	    				ON CONFLICT DO NOTHING;`,
 		article.SourceID,
 		article.Title,
 		article.Link,
 		article.Summary,
+		article.ImageURL,
+		pq.Array(article.Categories),
+		article.Language,
 		article.PublishedAt,
 	); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
@@ -49,6 +53,8 @@ func (s *ArticlePostgresStorage) Store(ctx context.Context, article models.Artic
 }
 
 // AllNotPosted retrieves articles that have not been marked as posted, filtered by a timestamp and limited by a maximum number.
+// Articles approved by moderators are not filtered by the timestamp and come first, followed by breaking news.
+// Articles requeued by admins are filtered by the time they were requeued instead of the time they were published.
 func (s *ArticlePostgresStorage) AllNotPosted(ctx context.Context, since time.Time, limit uint64) ([]models.Article, error) {
 	const op = "storage.ArticlePostgresStorage.AllNotPosted"
 
@@ -64,7 +70,10 @@ func (s *ArticlePostgresStorage) AllNotPosted(ctx context.Context, since time.Ti
 	if err := conn.SelectContext(
 		ctx,
 		&dbArticles,
-		`SELECT * FROM articles WHERE posted_at IS NULL AND published_at >= $1::timestamp ORDER BY published_at DESC LIMIT $2;`,
+		articlesQuery+` WHERE a.posted_at IS NULL
+						AND (a.next_attempt_at IS NULL OR a.next_attempt_at <= NOW() AT TIME ZONE 'UTC')
+						AND (a.status = 'approved' OR (a.status = 'pending' AND COALESCE(a.requeued_at, a.published_at) >= $1::timestamp))
+						ORDER BY a.status = 'approved' DESC, a.breaking_at IS NOT NULL DESC, a.published_at DESC LIMIT $2;`,
 		since.UTC().Format(time.RFC3339),
 		limit,
 	); err != nil {
@@ -74,21 +83,66 @@ func (s *ArticlePostgresStorage) AllNotPosted(ctx context.Context, since time.Ti
 	articles := (make([]models.Article, 0, len(dbArticles)))
 
 	for _, dbArticle := range dbArticles {
-		articles = append(articles, models.Article{
-			ID:          dbArticle.ID,
-			SourceID:    dbArticle.SourceID,
-			Title:       dbArticle.Title,
-			Link:        dbArticle.Link,
-			Summary:     dbArticle.Summary.String,
-			PublishedAt: dbArticle.PublishedAt,
-			CreatedAt:   dbArticle.CreatedAt,
-		})
+		articles = append(articles, dbArticle.toModel())
 	}
 
 	return articles, nil
 }
 
-// MarkAsPosted updates the posted_at timestamp of an article, marking it as posted.
+// ArticleByID retrieves an article by its ID.
+func (s *ArticlePostgresStorage) ArticleByID(ctx context.Context, id int64) (models.Article, error) {
+	const op = "storage.ArticlePostgresStorage.ArticleByID"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return models.Article{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	var dbArticle dbArticleWithPriority
+
+	if err := conn.GetContext(ctx, &dbArticle, articlesQuery+" WHERE a.id = $1;", id); err != nil {
+		return models.Article{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return dbArticle.toModel(), nil
+}
+
+// Claim marks an article as being sent. The article must still be in the status it was loaded with.
+// It returns false if the article has already been claimed or posted, in which case it must not be sent.
+func (s *ArticlePostgresStorage) Claim(ctx context.Context, article models.Article) (bool, error) {
+	const op = "storage.ArticlePostgresStorage.Claim"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	res, err := conn.ExecContext(
+		ctx,
+		`UPDATE articles SET status = $1, claimed_at = $2::timestamp WHERE id = $3 AND status = $4 AND posted_at IS NULL;`,
+		models.ArticleStatusSending,
+		time.Now().UTC().Format(time.RFC3339),
+		article.ID,
+		article.Status,
+	)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return affected > 0, nil
+}
+
+// MarkAsPosted updates the posted_at timestamp of a claimed article, marking it as posted.
+// It also stores the details of the Telegram message the article was posted as.
 func (s *ArticlePostgresStorage) MarkAsPosted(ctx context.Context, article models.Article) error {
 	const op = "storage.ArticlePostgresStorage.MarkAsPosted"
 
@@ -101,8 +155,559 @@ func (s *ArticlePostgresStorage) MarkAsPosted(ctx context.Context, article model
 
 	if _, err := conn.ExecContext(
 		ctx,
-		`UPDATE articles SET posted_at = $1::timestamp WHERE id = $2;`,
+		`UPDATE articles SET
+						posted_at = $1::timestamp,
+						status = $2,
+						chat_id = $3,
+						message_id = $4,
+						post_summary = $5,
+						post_title = $6,
+						post_has_photo = $7,
+						language = $8,
+						hashtags = $9,
+						prompt_version = $10,
+						last_error = NULL
+						WHERE id = $11;`,
 		time.Now().UTC().Format(time.RFC3339),
+		models.ArticleStatusPosted,
+		article.ChatID,
+		article.MessageID,
+		article.PostSummary,
+		article.PostTitle,
+		article.PostHasPhoto,
+		article.Language,
+		pq.Array(article.Hashtags),
+		article.PromptVersion,
+		article.ID,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ArticleByMessage retrieves the article that was posted as the given Telegram message.
+func (s *ArticlePostgresStorage) ArticleByMessage(ctx context.Context, chatID int64, messageID int) (models.Article, error) {
+	const op = "storage.ArticlePostgresStorage.ArticleByMessage"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return models.Article{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	var dbArticle dbArticleWithPriority
+
+	if err := conn.GetContext(
+		ctx,
+		&dbArticle,
+		articlesQuery+" WHERE a.chat_id = $1 AND a.message_id = $2;",
+		chatID,
+		messageID,
+	); err != nil {
+		return models.Article{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return dbArticle.toModel(), nil
+}
+
+// UpdatePostSummary stores the summary shown in the channel message of a posted article,
+// along with the version of the prompt it was generated with.
+func (s *ArticlePostgresStorage) UpdatePostSummary(ctx context.Context, article models.Article, summary string) error {
+	const op = "storage.ArticlePostgresStorage.UpdatePostSummary"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(
+		ctx,
+		`UPDATE articles SET post_summary = $1, prompt_version = $2 WHERE id = $3;`,
+		summary,
+		article.PromptVersion,
+		article.ID,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// MarkAsRetracted marks a posted article whose channel message has been deleted.
+func (s *ArticlePostgresStorage) MarkAsRetracted(ctx context.Context, article models.Article) error {
+	const op = "storage.ArticlePostgresStorage.MarkAsRetracted"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(
+		ctx,
+		`UPDATE articles SET status = $1 WHERE id = $2;`,
+		models.ArticleStatusRetracted,
+		article.ID,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// MarkAsInModeration moves a claimed article to the moderation queue. It stores the message the article
+// was sent to moderators as, along with the summary, the translated title, the hashtags and the lead image to post once it is approved.
+func (s *ArticlePostgresStorage) MarkAsInModeration(ctx context.Context, article models.Article) error {
+	const op = "storage.ArticlePostgresStorage.MarkAsInModeration"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(
+		ctx,
+		`UPDATE articles SET
+						status = $1,
+						moderation_chat_id = $2,
+						moderation_message_id = $3,
+						moderation_at = $4::timestamp,
+						post_summary = $5,
+						post_title = $6,
+						image_url = $7,
+						language = $8,
+						hashtags = $9,
+						prompt_version = $10,
+						last_error = NULL
+						WHERE id = $11;`,
+		models.ArticleStatusModeration,
+		article.ModerationChatID,
+		article.ModerationMessageID,
+		time.Now().UTC().Format(time.RFC3339),
+		article.PostSummary,
+		article.PostTitle,
+		article.ImageURL,
+		article.Language,
+		pq.Array(article.Hashtags),
+		article.PromptVersion,
+		article.ID,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// AllInModeration retrieves articles that have been waiting for moderation since before the given time.
+func (s *ArticlePostgresStorage) AllInModeration(ctx context.Context, before time.Time) ([]models.Article, error) {
+	const op = "storage.ArticlePostgresStorage.AllInModeration"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	var dbArticles []dbArticleWithPriority
+
+	if err := conn.SelectContext(
+		ctx,
+		&dbArticles,
+		articlesQuery+` WHERE a.status = $1 AND a.moderation_at < $2::timestamp ORDER BY a.moderation_at;`,
+		models.ArticleStatusModeration,
+		before.UTC().Format(time.RFC3339),
+	); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	articles := make([]models.Article, 0, len(dbArticles))
+
+	for _, dbArticle := range dbArticles {
+		articles = append(articles, dbArticle.toModel())
+	}
+
+	return articles, nil
+}
+
+// Transition changes the status of an article if it is still in the expected status.
+// It returns false if the article is in another status, for example because it was changed concurrently.
+func (s *ArticlePostgresStorage) Transition(ctx context.Context, id int64, from, to models.ArticleStatus) (bool, error) {
+	const op = "storage.ArticlePostgresStorage.Transition"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	res, err := conn.ExecContext(ctx, `UPDATE articles SET status = $1 WHERE id = $2 AND status = $3;`, to, id, from)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return affected > 0, nil
+}
+
+// ReleaseStale moves articles that have been claimed for longer than the lease to the dead-letter state.
+// Whether such articles have reached the channel is unknown, so they are left for admins to review
+// rather than being sent again. It returns the number of released articles.
+func (s *ArticlePostgresStorage) ReleaseStale(ctx context.Context, lease time.Duration) (int64, error) {
+	const op = "storage.ArticlePostgresStorage.ReleaseStale"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	res, err := conn.ExecContext(
+		ctx,
+		`UPDATE articles SET status = $1, last_error = $2 WHERE status = $3 AND claimed_at < $4::timestamp;`,
+		models.ArticleStatusDead,
+		"interrupted while sending, delivery state is unknown",
+		models.ArticleStatusSending,
+		time.Now().Add(-lease).UTC().Format(time.RFC3339),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return affected, nil
+}
+
+// AllUnscored retrieves articles waiting to be posted, published since the given time,
+// that have not been checked against the breaking-news rules yet.
+func (s *ArticlePostgresStorage) AllUnscored(ctx context.Context, since time.Time, limit uint64) ([]models.Article, error) {
+	const op = "storage.ArticlePostgresStorage.AllUnscored"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	var dbArticles []dbArticleWithPriority
+
+	if err := conn.SelectContext(
+		ctx,
+		&dbArticles,
+		articlesQuery+` WHERE a.status = $1 AND a.posted_at IS NULL AND a.urgency IS NULL AND a.published_at >= $2::timestamp
+						ORDER BY a.published_at DESC LIMIT $3;`,
+		models.ArticleStatusPending,
+		since.UTC().Format(time.RFC3339),
+		limit,
+	); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	articles := make([]models.Article, 0, len(dbArticles))
+
+	for _, dbArticle := range dbArticles {
+		articles = append(articles, dbArticle.toModel())
+	}
+
+	return articles, nil
+}
+
+// UpdateUrgency stores the urgency score of an article and, if it is breaking news, the time it was marked as such.
+func (s *ArticlePostgresStorage) UpdateUrgency(ctx context.Context, article models.Article) error {
+	const op = "storage.ArticlePostgresStorage.UpdateUrgency"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(
+		ctx,
+		`UPDATE articles SET urgency = $1, breaking_at = CASE WHEN $2 THEN NOW() AT TIME ZONE 'UTC' END WHERE id = $3;`,
+		article.Urgency,
+		article.Breaking,
+		article.ID,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// AllUnenriched retrieves articles waiting to be posted that were published since the given time
+// and have not been enriched yet, newest first. Articles whose enrichment failed maxAttempts times are left out.
+func (s *ArticlePostgresStorage) AllUnenriched(
+	ctx context.Context,
+	since time.Time,
+	maxAttempts int,
+	limit uint64,
+) ([]models.Article, error) {
+	const op = "storage.ArticlePostgresStorage.AllUnenriched"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	var dbArticles []dbArticleWithPriority
+
+	if err := conn.SelectContext(
+		ctx,
+		&dbArticles,
+		articlesQuery+` WHERE a.status = $1 AND a.posted_at IS NULL AND a.enriched_at IS NULL AND a.published_at >= $2::timestamp
+						AND a.enrich_attempts < $3
+						ORDER BY a.published_at DESC LIMIT $4;`,
+		models.ArticleStatusPending,
+		since.UTC().Format(time.RFC3339),
+		maxAttempts,
+		limit,
+	); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	articles := make([]models.Article, 0, len(dbArticles))
+
+	for _, dbArticle := range dbArticles {
+		articles = append(articles, dbArticle.toModel())
+	}
+
+	return articles, nil
+}
+
+// UpdateEnrichment stores the enrichment of an article.
+func (s *ArticlePostgresStorage) UpdateEnrichment(ctx context.Context, article models.Article) error {
+	const op = "storage.ArticlePostgresStorage.UpdateEnrichment"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(
+		ctx,
+		`UPDATE articles SET
+						enriched_summary = $1,
+						tags = $2,
+						relevance = $3,
+						clickbait = $4,
+						sentiment = $5,
+						enriched_prompt_version = $6,
+						enriched_at = NOW() AT TIME ZONE 'UTC'
+						WHERE id = $7;`,
+		article.Enrichment.Summary,
+		pq.Array(article.Enrichment.Tags),
+		article.Enrichment.Relevance,
+		article.Enrichment.Clickbait,
+		article.Enrichment.Sentiment,
+		article.Enrichment.PromptVersion,
+		article.ID,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RecordEnrichmentFailure records a failed enrichment of an article, so that it is given up after the maximum attempts.
+func (s *ArticlePostgresStorage) RecordEnrichmentFailure(ctx context.Context, id int64) error {
+	const op = "storage.ArticlePostgresStorage.RecordEnrichmentFailure"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `UPDATE articles SET enrich_attempts = enrich_attempts + 1 WHERE id = $1;`, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// MarkAsFiltered marks an article waiting to be posted, or claimed for posting, as skipped by the enrichment rules,
+// recording the reason as its last error.
+func (s *ArticlePostgresStorage) MarkAsFiltered(ctx context.Context, id int64, reason string) error {
+	const op = "storage.ArticlePostgresStorage.MarkAsFiltered"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(
+		ctx,
+		`UPDATE articles SET status = $1, last_error = $2 WHERE id = $3 AND status IN ($4, $5);`,
+		models.ArticleStatusFiltered,
+		reason,
+		id,
+		models.ArticleStatusPending,
+		models.ArticleStatusSending,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// CountBreaking returns the number of articles marked as breaking news since the given time.
+func (s *ArticlePostgresStorage) CountBreaking(ctx context.Context, since time.Time) (int, error) {
+	const op = "storage.ArticlePostgresStorage.CountBreaking"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	var count int
+
+	if err := conn.GetContext(
+		ctx,
+		&count,
+		`SELECT COUNT(*) FROM articles WHERE breaking_at >= $1::timestamp;`,
+		since.UTC().Format(time.RFC3339),
+	); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+// MarkAsExpired marks articles waiting to be posted that were published before the given time as expired.
+// Articles approved by moderators never expire, and articles requeued by admins expire by the time
+// they were requeued. It returns the number of expired articles.
+func (s *ArticlePostgresStorage) MarkAsExpired(ctx context.Context, before time.Time) (int64, error) {
+	const op = "storage.ArticlePostgresStorage.MarkAsExpired"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	res, err := conn.ExecContext(
+		ctx,
+		`UPDATE articles SET status = $1, expired_at = NOW() AT TIME ZONE 'UTC'
+						WHERE status = $2 AND posted_at IS NULL AND COALESCE(requeued_at, published_at) < $3::timestamp;`,
+		models.ArticleStatusExpired,
+		models.ArticleStatusPending,
+		before.UTC().Format(time.RFC3339),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return affected, nil
+}
+
+// ExpiredCounts retrieves the number of articles per source that expired since the given time,
+// ordered by the number of articles, largest first.
+func (s *ArticlePostgresStorage) ExpiredCounts(ctx context.Context, since time.Time) ([]models.ExpiredCount, error) {
+	const op = "storage.ArticlePostgresStorage.ExpiredCounts"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	var counts []models.ExpiredCount
+
+	if err := conn.SelectContext(
+		ctx,
+		&counts,
+		`SELECT s.name AS source_name, COUNT(*) AS count FROM articles a
+						JOIN sources s ON s.id = a.source_id
+						WHERE a.status = $1 AND a.expired_at >= $2::timestamp
+						GROUP BY s.name
+						ORDER BY count DESC, s.name;`,
+		models.ArticleStatusExpired,
+		since.UTC().Format(time.RFC3339),
+	); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return counts, nil
+}
+
+// MarkAsFailed records a failed attempt to post an article and schedules the next attempt.
+// The article returns to the status it had before it was claimed.
+func (s *ArticlePostgresStorage) MarkAsFailed(ctx context.Context, article models.Article, reason string, retryAt time.Time) error {
+	const op = "storage.ArticlePostgresStorage.MarkAsFailed"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(
+		ctx,
+		`UPDATE articles SET send_attempts = send_attempts + 1, last_error = $1, next_attempt_at = $2::timestamp, status = $3 WHERE id = $4;`,
+		reason,
+		retryAt.UTC().Format(time.RFC3339),
+		article.Status,
+		article.ID,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// MarkAsDead records the last failed attempt to post an article and moves it to the dead-letter state.
+func (s *ArticlePostgresStorage) MarkAsDead(ctx context.Context, article models.Article, reason string) error {
+	const op = "storage.ArticlePostgresStorage.MarkAsDead"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(
+		ctx,
+		`UPDATE articles SET send_attempts = send_attempts + 1, last_error = $1, next_attempt_at = NULL, status = $2 WHERE id = $3;`,
+		reason,
+		models.ArticleStatusDead,
 		article.ID,
 	); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
@@ -111,14 +716,167 @@ func (s *ArticlePostgresStorage) MarkAsPosted(ctx context.Context, article model
 	return nil
 }
 
+// AllDead retrieves articles in the dead-letter state, most recent first.
+func (s *ArticlePostgresStorage) AllDead(ctx context.Context, limit uint64) ([]models.Article, error) {
+	const op = "storage.ArticlePostgresStorage.AllDead"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	var dbArticles []dbArticleWithPriority
+
+	if err := conn.SelectContext(
+		ctx,
+		&dbArticles,
+		articlesQuery+` WHERE a.status = $1 ORDER BY a.published_at DESC LIMIT $2;`,
+		models.ArticleStatusDead,
+		limit,
+	); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	articles := make([]models.Article, 0, len(dbArticles))
+
+	for _, dbArticle := range dbArticles {
+		articles = append(articles, dbArticle.toModel())
+	}
+
+	return articles, nil
+}
+
+// Requeue moves a dead-lettered article back to the pending state and resets its attempts.
+// The time it is requeued at is recorded, so that an old article is not expired before it is retried.
+func (s *ArticlePostgresStorage) Requeue(ctx context.Context, id int64) error {
+	const op = "storage.ArticlePostgresStorage.Requeue"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	res, err := conn.ExecContext(
+		ctx,
+		`UPDATE articles SET status = $1, send_attempts = 0, last_error = NULL, next_attempt_at = NULL,
+						requeued_at = NOW() AT TIME ZONE 'UTC' WHERE id = $2 AND status = $3;`,
+		models.ArticleStatusPending,
+		id,
+		models.ArticleStatusDead,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: article %d is not dead-lettered", op, id)
+	}
+
+	return nil
+}
+
+// articlesQuery selects articles together with the name of their source.
+const articlesQuery = `SELECT a.*, s.name AS source_name, tp.url AS telegraph_url FROM articles a
+						JOIN sources s ON s.id = a.source_id
+						LEFT JOIN telegraph_pages tp ON tp.article_id = a.id`
+
 // dbArticleWithPriority represents the structure of the database rows retrieved with additional source priority.
 type dbArticleWithPriority struct {
-	ID          int64          `db:"id"`
-	SourceID    int64          `db:"source_id"`
-	Title       string         `db:"title"`
-	Link        string         `db:"link"`
-	Summary     sql.NullString `db:"summary"`
-	PublishedAt time.Time      `db:"published_at"`
-	PostedAt    sql.NullTime   `db:"posted_at"`
-	CreatedAt   time.Time      `db:"created_at"`
+	ID           int64           `db:"id"`
+	SourceID     int64           `db:"source_id"`
+	Title        string          `db:"title"`
+	Link         string          `db:"link"`
+	Summary      sql.NullString  `db:"summary"`
+	ImageURL     sql.NullString  `db:"image_url"`
+	Categories   pq.StringArray  `db:"categories"`
+	SourceName   string          `db:"source_name"`
+	Language     sql.NullString  `db:"language"`
+	Status       string          `db:"status"`
+	SendAttempts int             `db:"send_attempts"`
+	LastError    sql.NullString  `db:"last_error"`
+	NextAttempt  sql.NullTime    `db:"next_attempt_at"`
+	ClaimedAt    sql.NullTime    `db:"claimed_at"`
+	ChatID       sql.NullInt64   `db:"chat_id"`
+	MessageID    sql.NullInt64   `db:"message_id"`
+	PostSummary  sql.NullString  `db:"post_summary"`
+	PostTitle    sql.NullString  `db:"post_title"`
+	Hashtags     pq.StringArray  `db:"hashtags"`
+	TelegraphURL sql.NullString  `db:"telegraph_url"`
+	Urgency      sql.NullFloat64 `db:"urgency"`
+	BreakingAt   sql.NullTime    `db:"breaking_at"`
+	EnrichedAt   sql.NullTime    `db:"enriched_at"`
+	EnrSummary   sql.NullString  `db:"enriched_summary"`
+	Tags         pq.StringArray  `db:"tags"`
+	Relevance    sql.NullFloat64 `db:"relevance"`
+	Clickbait    sql.NullBool    `db:"clickbait"`
+	Sentiment    sql.NullFloat64 `db:"sentiment"`
+	EnrVersion   sql.NullString  `db:"enriched_prompt_version"`
+	EnrAttempts  int             `db:"enrich_attempts"`
+	PromptVer    sql.NullString  `db:"prompt_version"`
+	PostHasPhoto bool            `db:"post_has_photo"`
+	ModChatID    sql.NullInt64   `db:"moderation_chat_id"`
+	ModMessageID sql.NullInt64   `db:"moderation_message_id"`
+	ModeratedAt  sql.NullTime    `db:"moderation_at"`
+	ExpiredAt    sql.NullTime    `db:"expired_at"`
+	RequeuedAt   sql.NullTime    `db:"requeued_at"`
+	PublishedAt  time.Time       `db:"published_at"`
+	PostedAt     sql.NullTime    `db:"posted_at"`
+	CreatedAt    time.Time       `db:"created_at"`
+}
+
+// toModel converts the database row to an Article model.
+func (a dbArticleWithPriority) toModel() models.Article {
+	return models.Article{
+		ID:                  a.ID,
+		SourceID:            a.SourceID,
+		Title:               a.Title,
+		Link:                a.Link,
+		Summary:             a.Summary.String,
+		ImageURL:            a.ImageURL.String,
+		Categories:          a.Categories,
+		SourceName:          a.SourceName,
+		Language:            a.Language.String,
+		Status:              models.ArticleStatus(a.Status),
+		SendAttempts:        a.SendAttempts,
+		LastError:           a.LastError.String,
+		ChatID:              a.ChatID.Int64,
+		MessageID:           int(a.MessageID.Int64),
+		PostSummary:         a.PostSummary.String,
+		PostTitle:           a.PostTitle.String,
+		Hashtags:            a.Hashtags,
+		TelegraphURL:        a.TelegraphURL.String,
+		Urgency:             a.Urgency.Float64,
+		Breaking:            a.BreakingAt.Valid,
+		Enrichment:          a.enrichment(),
+		PromptVersion:       a.PromptVer.String,
+		PostHasPhoto:        a.PostHasPhoto,
+		ModerationChatID:    a.ModChatID.Int64,
+		ModerationMessageID: int(a.ModMessageID.Int64),
+		ModeratedAt:         a.ModeratedAt.Time,
+		PublishedAt:         a.PublishedAt,
+		PostedAt:            a.PostedAt.Time,
+		CreatedAt:           a.CreatedAt,
+	}
+}
+
+// enrichment converts the enrichment columns of the database row to an Enrichment model.
+func (a dbArticleWithPriority) enrichment() models.Enrichment {
+	return models.Enrichment{
+		Summary:       a.EnrSummary.String,
+		Tags:          a.Tags,
+		Relevance:     a.Relevance.Float64,
+		Clickbait:     a.Clickbait.Bool,
+		Sentiment:     a.Sentiment.Float64,
+		PromptVersion: a.EnrVersion.String,
+		EnrichedAt:    a.EnrichedAt.Time,
+	}
 }
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+	_ "github.com/lib/pq"
+)
+
+// testDB connects to the migrated database given by EW_TEST_DATABASE_DSN, skipping the test without it.
+func testDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	dsn := os.Getenv("EW_TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("EW_TEST_DATABASE_DSN is not set")
+	}
+
+	db, err := sqlx.Connect("postgres", dsn)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+// testSource stores a source whose articles are deleted along with it when the test ends.
+func testSource(t *testing.T, db *sqlx.DB) int64 {
+	t.Helper()
+
+	var id int64
+
+	if err := db.Get(&id, "INSERT INTO sources (name, url) VALUES ($1, $2) RETURNING id", t.Name(), "https://example.com/feed"); err != nil {
+		t.Fatalf("store source: %v", err)
+	}
+
+	t.Cleanup(func() {
+		db.MustExec("DELETE FROM articles WHERE source_id = $1", id)
+		db.MustExec("DELETE FROM sources WHERE id = $1", id)
+	})
+
+	return id
+}
+
+// testArticle stores an article of the source published at the given time and returns its ID.
+func testArticle(t *testing.T, db *sqlx.DB, storage *ArticlePostgresStorage, sourceID int64, publishedAt time.Time) int64 {
+	t.Helper()
+
+	link := fmt.Sprintf("https://example.com/%d/%d", sourceID, publishedAt.UnixNano())
+
+	if err := storage.Store(context.Background(), models.Article{
+		SourceID:    sourceID,
+		Title:       "Title",
+		Link:        link,
+		PublishedAt: publishedAt,
+	}); err != nil {
+		t.Fatalf("store article: %v", err)
+	}
+
+	var id int64
+
+	if err := db.Get(&id, "SELECT id FROM articles WHERE link = $1", link); err != nil {
+		t.Fatalf("get article: %v", err)
+	}
+
+	return id
+}
+
+func TestArticlePostgresStorageRequeueOldDeadLetter(t *testing.T) {
+	ctx := context.Background()
+	db := testDB(t)
+	storage := NewArticleStorage(db)
+	sourceID := testSource(t, db)
+
+	expiry := 24 * time.Hour
+	publishedAt := time.Now().Add(-2 * expiry)
+
+	requeued := testArticle(t, db, storage, sourceID, publishedAt)
+	stale := testArticle(t, db, storage, sourceID, publishedAt.Add(time.Second))
+
+	article, err := storage.ArticleByID(ctx, requeued)
+	if err != nil {
+		t.Fatalf("get article: %v", err)
+	}
+
+	if err := storage.MarkAsDead(ctx, article, "send failed"); err != nil {
+		t.Fatalf("mark as dead: %v", err)
+	}
+
+	if err := storage.Requeue(ctx, requeued); err != nil {
+		t.Fatalf("requeue: %v", err)
+	}
+
+	if _, err := storage.MarkAsExpired(ctx, time.Now().Add(-expiry)); err != nil {
+		t.Fatalf("mark as expired: %v", err)
+	}
+
+	statuses := map[int64]models.ArticleStatus{requeued: models.ArticleStatusPending, stale: models.ArticleStatusExpired}
+
+	for id, want := range statuses {
+		article, err := storage.ArticleByID(ctx, id)
+		if err != nil {
+			t.Fatalf("get article: %v", err)
+		}
+
+		if article.Status != want {
+			t.Errorf("status of article %d = %s, want %s", id, article.Status, want)
+		}
+	}
+
+	articles, err := storage.AllNotPosted(ctx, time.Now().Add(-expiry), 1000)
+	if err != nil {
+		t.Fatalf("all not posted: %v", err)
+	}
+
+	for _, article := range articles {
+		if article.ID == requeued {
+			return
+		}
+	}
+
+	t.Errorf("requeued article %d is not waiting to be posted", requeued)
+}
@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+)
+
+// ContentPostgresStorage provides storage for the extracted content of articles using a PostgreSQL database.
+type ContentPostgresStorage struct {
+	db *sqlx.DB
+}
+
+// NewContentStorage initializes a new instance of ContentPostgresStorage.
+func NewContentStorage(db *sqlx.DB) *ContentPostgresStorage {
+	return &ContentPostgresStorage{db: db}
+}
+
+// Content retrieves the extracted content of an article.
+// If the content has not been extracted, an empty content with a zero ExtractedAt is returned.
+func (s *ContentPostgresStorage) Content(ctx context.Context, articleID int64) (models.ArticleContent, error) {
+	const op = "storage.ContentPostgresStorage.Content"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return models.ArticleContent{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	var contentDB dbArticleContent
+
+	if err := conn.GetContext(
+		ctx,
+		&contentDB,
+		"SELECT * FROM article_contents WHERE article_id = $1 AND extracted_at IS NOT NULL",
+		articleID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ArticleContent{ArticleID: articleID}, nil
+		}
+
+		return models.ArticleContent{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return models.ArticleContent{
+		ArticleID:   contentDB.ArticleID,
+		Text:        contentDB.Text,
+		HTML:        contentDB.HTML,
+		Byline:      contentDB.Byline,
+		WordCount:   contentDB.WordCount,
+		ImageURL:    contentDB.ImageURL,
+		SiteName:    contentDB.SiteName,
+		ExtractedAt: contentDB.ExtractedAt.Time,
+	}, nil
+}
+
+// AllNotExtracted retrieves articles waiting to be posted whose content has not been extracted yet.
+// Articles whose extraction failed maxAttempts times are left out.
+func (s *ContentPostgresStorage) AllNotExtracted(ctx context.Context, maxAttempts int, limit uint64) ([]models.Article, error) {
+	const op = "storage.ContentPostgresStorage.AllNotExtracted"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	var dbArticles []dbArticleWithPriority
+
+	if err := conn.SelectContext(
+		ctx,
+		&dbArticles,
+		`SELECT a.*, s.name AS source_name FROM articles a
+						JOIN sources s ON s.id = a.source_id
+						LEFT JOIN article_contents c ON c.article_id = a.id
+						WHERE a.posted_at IS NULL
+						AND a.status = $1
+						AND c.extracted_at IS NULL
+						AND COALESCE(c.attempts, 0) < $2
+						ORDER BY a.published_at DESC LIMIT $3;`,
+		models.ArticleStatusPending,
+		maxAttempts,
+		limit,
+	); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	articles := make([]models.Article, 0, len(dbArticles))
+
+	for _, dbArticle := range dbArticles {
+		articles = append(articles, dbArticle.toModel())
+	}
+
+	return articles, nil
+}
+
+// StoreContent stores the extracted content of an article, replacing any previous extraction.
+func (s *ContentPostgresStorage) StoreContent(ctx context.Context, content models.ArticleContent) error {
+	const op = "storage.ContentPostgresStorage.StoreContent"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(
+		ctx,
+		`INSERT INTO article_contents (article_id, text, html, byline, word_count, image_url, site_name, attempts, extracted_at, updated_at)
+						VALUES ($1, $2, $3, $4, $5, $6, $7, 1, NOW(), NOW())
+						ON CONFLICT (article_id) DO UPDATE SET
+							text = EXCLUDED.text,
+							html = EXCLUDED.html,
+							byline = EXCLUDED.byline,
+							word_count = EXCLUDED.word_count,
+							image_url = EXCLUDED.image_url,
+							site_name = EXCLUDED.site_name,
+							attempts = article_contents.attempts + 1,
+							last_error = NULL,
+							extracted_at = EXCLUDED.extracted_at,
+							updated_at = EXCLUDED.updated_at;`,
+		content.ArticleID,
+		content.Text,
+		content.HTML,
+		content.Byline,
+		content.WordCount,
+		content.ImageURL,
+		content.SiteName,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// MarkExtractionFailed records a failed attempt to extract the content of an article.
+func (s *ContentPostgresStorage) MarkExtractionFailed(ctx context.Context, articleID int64, reason string) error {
+	const op = "storage.ContentPostgresStorage.MarkExtractionFailed"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(
+		ctx,
+		`INSERT INTO article_contents (article_id, attempts, last_error, updated_at)
+						VALUES ($1, 1, $2, NOW())
+						ON CONFLICT (article_id) DO UPDATE SET
+							attempts = article_contents.attempts + 1,
+							last_error = EXCLUDED.last_error,
+							updated_at = EXCLUDED.updated_at;`,
+		articleID,
+		reason,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// dbArticleContent maps database rows to Go structs for internal use.
+type dbArticleContent struct {
+	ArticleID   int64          `db:"article_id"`
+	Text        string         `db:"text"`
+	HTML        string         `db:"html"`
+	Byline      string         `db:"byline"`
+	WordCount   int            `db:"word_count"`
+	ImageURL    string         `db:"image_url"`
+	SiteName    string         `db:"site_name"`
+	Attempts    int            `db:"attempts"`
+	LastError   sql.NullString `db:"last_error"`
+	ExtractedAt sql.NullTime   `db:"extracted_at"`
+	UpdatedAt   time.Time      `db:"updated_at"`
+}
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE articles ADD COLUMN categories TEXT[];
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE articles DROP COLUMN IF EXISTS categories;
+-- +goose StatementEnd
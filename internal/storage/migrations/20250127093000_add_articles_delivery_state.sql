@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE articles
+    ADD COLUMN status VARCHAR(32) NOT NULL DEFAULT 'pending',
+    ADD COLUMN send_attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN last_error TEXT,
+    ADD COLUMN next_attempt_at TIMESTAMP;
+
+CREATE INDEX idx_articles_status ON articles (status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_articles_status;
+
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS send_attempts,
+    DROP COLUMN IF EXISTS last_error,
+    DROP COLUMN IF EXISTS next_attempt_at;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE articles
+    ADD COLUMN claimed_at TIMESTAMP,
+    ADD COLUMN chat_id BIGINT,
+    ADD COLUMN message_id BIGINT;
+
+UPDATE articles SET status = 'posted' WHERE posted_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE articles SET status = 'pending' WHERE status IN ('posted', 'sending');
+
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS claimed_at,
+    DROP COLUMN IF EXISTS chat_id,
+    DROP COLUMN IF EXISTS message_id;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE articles
+    ADD COLUMN post_summary TEXT,
+    ADD COLUMN post_has_photo BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_articles_chat_message ON articles (chat_id, message_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_articles_chat_message;
+
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS post_summary,
+    DROP COLUMN IF EXISTS post_has_photo;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE articles
+    ADD COLUMN moderation_chat_id BIGINT,
+    ADD COLUMN moderation_message_id BIGINT,
+    ADD COLUMN moderation_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS moderation_chat_id,
+    DROP COLUMN IF EXISTS moderation_message_id,
+    DROP COLUMN IF EXISTS moderation_at;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE articles
+    ADD COLUMN language VARCHAR(8),
+    ADD COLUMN post_title TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS language,
+    DROP COLUMN IF EXISTS post_title;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE articles ADD COLUMN hashtags TEXT[];
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE articles DROP COLUMN IF EXISTS hashtags;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE telegraph_pages (
+    article_id INTEGER PRIMARY KEY,
+    access_token TEXT NOT NULL,
+    path TEXT NOT NULL,
+    url TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_telegraph_pages_article_id
+        FOREIGN KEY (article_id)
+            REFERENCES articles (id)
+            ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS telegraph_pages;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE articles ADD COLUMN expired_at TIMESTAMP;
+
+CREATE INDEX idx_articles_expired_at ON articles (expired_at) WHERE expired_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_articles_expired_at;
+
+ALTER TABLE articles DROP COLUMN IF EXISTS expired_at;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE articles
+    ADD COLUMN urgency REAL,
+    ADD COLUMN breaking_at TIMESTAMP;
+
+CREATE INDEX idx_articles_breaking_at ON articles (breaking_at) WHERE breaking_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_articles_breaking_at;
+
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS urgency,
+    DROP COLUMN IF EXISTS breaking_at;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE llm_usage (
+    id SERIAL PRIMARY KEY,
+    article_id INTEGER,
+    model TEXT NOT NULL,
+    purpose TEXT NOT NULL,
+    prompt_tokens INTEGER NOT NULL DEFAULT 0,
+    completion_tokens INTEGER NOT NULL DEFAULT 0,
+    cost NUMERIC(14, 8) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_llm_usage_article_id
+        FOREIGN KEY (article_id)
+            REFERENCES articles (id)
+            ON DELETE SET NULL
+);
+
+CREATE INDEX idx_llm_usage_created_at ON llm_usage (created_at);
+CREATE INDEX idx_llm_usage_article_id ON llm_usage (article_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS llm_usage;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE articles
+    ADD COLUMN enriched_summary TEXT,
+    ADD COLUMN tags TEXT[],
+    ADD COLUMN relevance REAL,
+    ADD COLUMN clickbait BOOLEAN,
+    ADD COLUMN sentiment REAL,
+    ADD COLUMN enriched_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS enriched_summary,
+    DROP COLUMN IF EXISTS tags,
+    DROP COLUMN IF EXISTS relevance,
+    DROP COLUMN IF EXISTS clickbait,
+    DROP COLUMN IF EXISTS sentiment,
+    DROP COLUMN IF EXISTS enriched_at;
+-- +goose StatementEnd
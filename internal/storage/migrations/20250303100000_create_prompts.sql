@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE prompts (
+    id SERIAL PRIMARY KEY,
+    scope VARCHAR(16) NOT NULL,
+    scope_id BIGINT NOT NULL DEFAULT 0,
+    version INTEGER NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (scope, scope_id, version)
+);
+
+ALTER TABLE articles
+    ADD COLUMN prompt_version TEXT,
+    ADD COLUMN enriched_prompt_version TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS prompt_version,
+    DROP COLUMN IF EXISTS enriched_prompt_version;
+
+DROP TABLE IF EXISTS prompts;
+-- +goose StatementEnd
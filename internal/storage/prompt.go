@@ -0,0 +1,187 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+)
+
+// PromptPostgresStorage provides storage for versioned prompt templates using a PostgreSQL database.
+type PromptPostgresStorage struct {
+	db *sqlx.DB
+}
+
+// NewPromptStorage initializes a new instance of PromptPostgresStorage.
+func NewPromptStorage(db *sqlx.DB) *PromptPostgresStorage {
+	return &PromptPostgresStorage{db: db}
+}
+
+// Prompt retrieves the latest version of the prompt of the given scope.
+// If no prompt is stored for the scope, an empty prompt is returned.
+func (s *PromptPostgresStorage) Prompt(ctx context.Context, scope models.PromptScope, scopeID int64) (models.Prompt, error) {
+	const op = "storage.PromptPostgresStorage.Prompt"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return models.Prompt{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	var promptDB dbPrompt
+
+	if err := conn.GetContext(
+		ctx,
+		&promptDB,
+		"SELECT * FROM prompts WHERE scope = $1 AND scope_id = $2 ORDER BY version DESC LIMIT 1",
+		scope,
+		scopeID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Prompt{Scope: scope, ScopeID: scopeID}, nil
+		}
+
+		return models.Prompt{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return promptDB.toModel(), nil
+}
+
+// PromptVersion retrieves the given version of the prompt of the given scope.
+// If there is no such version, an empty prompt is returned.
+func (s *PromptPostgresStorage) PromptVersion(
+	ctx context.Context,
+	scope models.PromptScope,
+	scopeID int64,
+	version int,
+) (models.Prompt, error) {
+	const op = "storage.PromptPostgresStorage.PromptVersion"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return models.Prompt{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	var promptDB dbPrompt
+
+	if err := conn.GetContext(
+		ctx,
+		&promptDB,
+		"SELECT * FROM prompts WHERE scope = $1 AND scope_id = $2 AND version = $3",
+		scope,
+		scopeID,
+		version,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Prompt{Scope: scope, ScopeID: scopeID}, nil
+		}
+
+		return models.Prompt{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return promptDB.toModel(), nil
+}
+
+// Prompts retrieves the versions of the prompt of the given scope, newest first.
+func (s *PromptPostgresStorage) Prompts(
+	ctx context.Context,
+	scope models.PromptScope,
+	scopeID int64,
+	limit uint64,
+) ([]models.Prompt, error) {
+	const op = "storage.PromptPostgresStorage.Prompts"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	var promptsDB []dbPrompt
+
+	if err := conn.SelectContext(
+		ctx,
+		&promptsDB,
+		"SELECT * FROM prompts WHERE scope = $1 AND scope_id = $2 ORDER BY version DESC LIMIT $3",
+		scope,
+		scopeID,
+		limit,
+	); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	prompts := make([]models.Prompt, 0, len(promptsDB))
+
+	for _, promptDB := range promptsDB {
+		prompts = append(prompts, promptDB.toModel())
+	}
+
+	return prompts, nil
+}
+
+// StorePrompt stores the body as the next version of the prompt of the given scope.
+// Earlier versions are kept as the history of the prompt.
+// It returns the number of the stored version.
+func (s *PromptPostgresStorage) StorePrompt(
+	ctx context.Context,
+	scope models.PromptScope,
+	scopeID int64,
+	body string,
+) (int, error) {
+	const op = "storage.PromptPostgresStorage.StorePrompt"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	var version int
+
+	if err := conn.GetContext(
+		ctx,
+		&version,
+		`INSERT INTO prompts (scope, scope_id, version, body, created_at)
+						SELECT $1, $2, COALESCE(MAX(version), 0) + 1, $3, NOW() AT TIME ZONE 'UTC'
+						FROM prompts WHERE scope = $1 AND scope_id = $2
+						RETURNING version;`,
+		scope,
+		scopeID,
+		body,
+	); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return version, nil
+}
+
+// dbPrompt maps database rows to Go structs for internal use.
+type dbPrompt struct {
+	ID        int64     `db:"id"`
+	Scope     string    `db:"scope"`
+	ScopeID   int64     `db:"scope_id"`
+	Version   int       `db:"version"`
+	Body      string    `db:"body"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// toModel converts the database row to a Prompt model.
+func (p dbPrompt) toModel() models.Prompt {
+	return models.Prompt{
+		ID:        p.ID,
+		Scope:     models.PromptScope(p.Scope),
+		ScopeID:   p.ScopeID,
+		Version:   p.Version,
+		Body:      p.Body,
+		CreatedAt: p.CreatedAt,
+	}
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+)
+
+// SummaryPostgresStorage provides storage for cached summaries using a PostgreSQL database.
+type SummaryPostgresStorage struct {
+	db *sqlx.DB
+}
+
+// NewSummaryStorage initializes a new instance of SummaryPostgresStorage.
+func NewSummaryStorage(db *sqlx.DB) *SummaryPostgresStorage {
+	return &SummaryPostgresStorage{db: db}
+}
+
+// CachedSummary retrieves the cached summary of the text with the given hash, generated by the model
+// with the given version of the prompt. If there is no such summary, an empty summary is returned.
+func (s *SummaryPostgresStorage) CachedSummary(
+	ctx context.Context,
+	textHash, model, promptVersion string,
+) (models.CachedSummary, error) {
+	const op = "storage.SummaryPostgresStorage.CachedSummary"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return models.CachedSummary{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	var summaryDB dbCachedSummary
+
+	if err := conn.GetContext(
+		ctx,
+		&summaryDB,
+		"SELECT * FROM summary_cache WHERE text_hash = $1 AND model = $2 AND prompt_version = $3",
+		textHash,
+		model,
+		promptVersion,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.CachedSummary{}, nil
+		}
+
+		return models.CachedSummary{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return models.CachedSummary(summaryDB), nil
+}
+
+// StoreSummary stores the summary in the cache, replacing any summary with the same key.
+func (s *SummaryPostgresStorage) StoreSummary(ctx context.Context, summary models.CachedSummary) error {
+	const op = "storage.SummaryPostgresStorage.StoreSummary"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(
+		ctx,
+		`INSERT INTO summary_cache (text_hash, model, prompt_version, summary, prompt_tokens, completion_tokens, created_at)
+						VALUES ($1, $2, $3, $4, $5, $6, NOW())
+						ON CONFLICT (text_hash, model, prompt_version) DO UPDATE SET
+							summary = EXCLUDED.summary,
+							prompt_tokens = EXCLUDED.prompt_tokens,
+							completion_tokens = EXCLUDED.completion_tokens,
+							created_at = EXCLUDED.created_at;`,
+		summary.TextHash,
+		summary.Model,
+		summary.PromptVersion,
+		summary.Summary,
+		summary.PromptTokens,
+		summary.CompletionTokens,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// dbCachedSummary maps database rows to Go structs for internal use.
+type dbCachedSummary struct {
+	TextHash         string    `db:"text_hash"`
+	Model            string    `db:"model"`
+	PromptVersion    string    `db:"prompt_version"`
+	Summary          string    `db:"summary"`
+	PromptTokens     int       `db:"prompt_tokens"`
+	CompletionTokens int       `db:"completion_tokens"`
+	CreatedAt        time.Time `db:"created_at"`
+}
@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+)
+
+// TelegraphPostgresStorage provides storage for the Telegraph pages of articles using a PostgreSQL database.
+type TelegraphPostgresStorage struct {
+	db *sqlx.DB
+}
+
+// NewTelegraphStorage initializes a new instance of TelegraphPostgresStorage.
+func NewTelegraphStorage(db *sqlx.DB) *TelegraphPostgresStorage {
+	return &TelegraphPostgresStorage{db: db}
+}
+
+// Page retrieves the Telegraph page of an article.
+// If the article has not been published to Telegraph, an empty page with no URL is returned.
+func (s *TelegraphPostgresStorage) Page(ctx context.Context, articleID int64) (models.TelegraphPage, error) {
+	const op = "storage.TelegraphPostgresStorage.Page"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return models.TelegraphPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	var pageDB dbTelegraphPage
+
+	if err := conn.GetContext(ctx, &pageDB, "SELECT * FROM telegraph_pages WHERE article_id = $1", articleID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.TelegraphPage{ArticleID: articleID}, nil
+		}
+
+		return models.TelegraphPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return models.TelegraphPage(pageDB), nil
+}
+
+// LastAccessToken retrieves the access token of the Telegraph account that created the latest page,
+// so that the account is reused across restarts. An empty token means no page has been created.
+func (s *TelegraphPostgresStorage) LastAccessToken(ctx context.Context) (string, error) {
+	const op = "storage.TelegraphPostgresStorage.LastAccessToken"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	var token string
+
+	if err := conn.GetContext(
+		ctx,
+		&token,
+		"SELECT access_token FROM telegraph_pages ORDER BY created_at DESC LIMIT 1",
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return token, nil
+}
+
+// StorePage stores the Telegraph page of an article, replacing any previous page.
+func (s *TelegraphPostgresStorage) StorePage(ctx context.Context, page models.TelegraphPage) error {
+	const op = "storage.TelegraphPostgresStorage.StorePage"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(
+		ctx,
+		`INSERT INTO telegraph_pages (article_id, access_token, path, url, created_at)
+						VALUES ($1, $2, $3, $4, NOW())
+						ON CONFLICT (article_id) DO UPDATE SET
+							access_token = EXCLUDED.access_token,
+							path = EXCLUDED.path,
+							url = EXCLUDED.url,
+							created_at = EXCLUDED.created_at;`,
+		page.ArticleID,
+		page.AccessToken,
+		page.Path,
+		page.URL,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// dbTelegraphPage maps database rows to Go structs for internal use.
+type dbTelegraphPage struct {
+	ArticleID   int64     `db:"article_id"`
+	AccessToken string    `db:"access_token"`
+	Path        string    `db:"path"`
+	URL         string    `db:"url"`
+	CreatedAt   time.Time `db:"created_at"`
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+)
+
+// TemplatePostgresStorage provides storage for post templates using a PostgreSQL database.
+type TemplatePostgresStorage struct {
+	db *sqlx.DB
+}
+
+// NewTemplateStorage initializes a new instance of TemplatePostgresStorage.
+func NewTemplateStorage(db *sqlx.DB) *TemplatePostgresStorage {
+	return &TemplatePostgresStorage{db: db}
+}
+
+// Template retrieves a post template by its name.
+// If no template is stored under the name, an empty template is returned.
+func (s *TemplatePostgresStorage) Template(ctx context.Context, name string) (models.PostTemplate, error) {
+	const op = "storage.TemplatePostgresStorage.Template"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return models.PostTemplate{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	var templateDB dbPostTemplate
+
+	if err := conn.GetContext(ctx, &templateDB, "SELECT * FROM post_templates WHERE name = $1", name); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.PostTemplate{Name: name}, nil
+		}
+
+		return models.PostTemplate{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return models.PostTemplate{
+		Name:      templateDB.Name,
+		Body:      templateDB.Body,
+		UpdatedAt: templateDB.UpdatedAt,
+	}, nil
+}
+
+// SetTemplate creates or replaces a post template.
+func (s *TemplatePostgresStorage) SetTemplate(ctx context.Context, template models.PostTemplate) error {
+	const op = "storage.TemplatePostgresStorage.SetTemplate"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(
+		ctx,
+		`INSERT INTO post_templates (name, body, updated_at)
+						VALUES ($1, $2, NOW())
+						ON CONFLICT (name) DO UPDATE SET body = EXCLUDED.body, updated_at = EXCLUDED.updated_at;`,
+		template.Name,
+		template.Body,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// dbPostTemplate maps database rows to Go structs for internal use.
+type dbPostTemplate struct {
+	Name      string    `db:"name"`
+	Body      string    `db:"body"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
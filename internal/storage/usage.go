@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+)
+
+// UsagePostgresStorage provides storage for the token usage of the language model using a PostgreSQL database.
+type UsagePostgresStorage struct {
+	db *sqlx.DB
+}
+
+// NewUsageStorage initializes a new instance of UsagePostgresStorage.
+func NewUsageStorage(db *sqlx.DB) *UsagePostgresStorage {
+	return &UsagePostgresStorage{db: db}
+}
+
+// StoreUsage stores the token usage and the cost of a request to the language model.
+func (s *UsagePostgresStorage) StoreUsage(ctx context.Context, usage models.LLMUsage) error {
+	const op = "storage.UsagePostgresStorage.StoreUsage"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(
+		ctx,
+		`INSERT INTO llm_usage (article_id, model, purpose, prompt_tokens, completion_tokens, cost, created_at)
+						VALUES ($1, $2, $3, $4, $5, $6, NOW() AT TIME ZONE 'UTC');`,
+		sql.NullInt64{Int64: usage.ArticleID, Valid: usage.ArticleID != 0},
+		usage.Model,
+		usage.Purpose,
+		usage.PromptTokens,
+		usage.CompletionTokens,
+		usage.Cost,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Spend returns the total cost of the requests made since the given time.
+func (s *UsagePostgresStorage) Spend(ctx context.Context, since time.Time) (float64, error) {
+	const op = "storage.UsagePostgresStorage.Spend"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	var spend float64
+
+	if err := conn.GetContext(
+		ctx,
+		&spend,
+		`SELECT COALESCE(SUM(cost), 0) FROM llm_usage WHERE created_at >= $1::timestamp;`,
+		since.UTC().Format(time.RFC3339),
+	); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return spend, nil
+}
+
+// Totals returns the total usage and cost per model of the requests made since the given time,
+// most expensive first.
+func (s *UsagePostgresStorage) Totals(ctx context.Context, since time.Time) ([]models.UsageTotal, error) {
+	const op = "storage.UsagePostgresStorage.Totals"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	var totals []models.UsageTotal
+
+	if err := conn.SelectContext(
+		ctx,
+		&totals,
+		`SELECT `+usageTotalColumns+` FROM llm_usage
+						WHERE created_at >= $1::timestamp
+						GROUP BY model
+						ORDER BY cost DESC, model;`,
+		since.UTC().Format(time.RFC3339),
+	); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return totals, nil
+}
+
+// ArticleTotals returns the total usage and cost per model of the requests made for the article.
+func (s *UsagePostgresStorage) ArticleTotals(ctx context.Context, articleID int64) ([]models.UsageTotal, error) {
+	const op = "storage.UsagePostgresStorage.ArticleTotals"
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer conn.Close()
+
+	var totals []models.UsageTotal
+
+	if err := conn.SelectContext(
+		ctx,
+		&totals,
+		`SELECT `+usageTotalColumns+` FROM llm_usage
+						WHERE article_id = $1
+						GROUP BY model
+						ORDER BY cost DESC, model;`,
+		articleID,
+	); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return totals, nil
+}
+
+// usageTotalColumns are the aggregated columns of models.UsageTotal.
+const usageTotalColumns = `model,
+						COUNT(*) AS requests,
+						COALESCE(SUM(prompt_tokens), 0) AS prompt_tokens,
+						COALESCE(SUM(completion_tokens), 0) AS completion_tokens,
+						COALESCE(SUM(cost), 0) AS cost`
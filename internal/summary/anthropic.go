@@ -0,0 +1,131 @@
+package summary
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// DefaultAnthropicBaseURL is the address of the Anthropic API.
+	DefaultAnthropicBaseURL = "https://api.anthropic.com"
+	// anthropicVersion is the version of the Anthropic API the backend is written against.
+	anthropicVersion = "2023-06-01"
+)
+
+// AnthropicBackend sends requests to the Anthropic Messages API.
+type AnthropicBackend struct {
+	apiKey     string
+	baseURL    string
+	model      string
+	httpClient *http.Client
+}
+
+// NewAnthropicBackend initializes a new instance of AnthropicBackend.
+// Parameters:
+// - apiKey: API key for authenticating with Anthropic.
+// - baseURL: The address of the API, e.g. DefaultAnthropicBaseURL.
+// - model: The model to be used for generating replies.
+// - httpClient: The HTTP client used for requests.
+// Returns:
+// - An initialized AnthropicBackend instance.
+func NewAnthropicBackend(apiKey, baseURL, model string, httpClient *http.Client) *AnthropicBackend {
+	return &AnthropicBackend{
+		apiKey:     apiKey,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		model:      model,
+		httpClient: httpClient,
+	}
+}
+
+// Complete sends the request as a message and returns the text of the reply.
+func (b *AnthropicBackend) Complete(ctx context.Context, req Request) (Completion, error) {
+	params := map[string]any{
+		"model":      b.model,
+		"max_tokens": req.MaxTokens,
+		"system":     req.System,
+		"messages": []map[string]string{
+			{"role": "user", "content": req.User},
+		},
+	}
+
+	if req.Temperature > 0 {
+		params["temperature"] = req.Temperature
+	}
+
+	body, err := json.Marshal(params)
+	if err != nil {
+		return Completion{}, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, b.baseURL+"/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return Completion{}, err
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-Api-Key", b.apiKey)
+	httpReq.Header.Set("Anthropic-Version", anthropicVersion)
+
+	resp, err := b.httpClient.Do(httpReq)
+	if err != nil {
+		return Completion{}, err
+	}
+	defer resp.Body.Close()
+
+	var msgResp struct {
+		Model   string `json:"model"`
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		Usage struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"usage"`
+		Error *struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&msgResp); err != nil {
+		return Completion{}, fmt.Errorf("unexpected anthropic response with status code %d: %w", resp.StatusCode, err)
+	}
+
+	if msgResp.Error != nil {
+		return Completion{}, fmt.Errorf("anthropic: %s: %s", msgResp.Error.Type, msgResp.Error.Message)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return Completion{}, fmt.Errorf("unexpected anthropic status code: %d", resp.StatusCode)
+	}
+
+	var text strings.Builder
+
+	for _, block := range msgResp.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+
+	if text.Len() == 0 {
+		return Completion{}, errors.New("no text in anthropic response")
+	}
+
+	return Completion{
+		Text:             text.String(),
+		Model:            msgResp.Model,
+		PromptTokens:     msgResp.Usage.InputTokens,
+		CompletionTokens: msgResp.Usage.OutputTokens,
+	}, nil
+}
+
+// Model returns the name of the model requests are sent to.
+func (b *AnthropicBackend) Model() string {
+	return b.model
+}
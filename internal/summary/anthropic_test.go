@@ -0,0 +1,108 @@
+package summary
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAnthropicBackendComplete(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    Completion
+		wantErr string
+	}{
+		{
+			name:   "success",
+			status: http.StatusOK,
+			body: `{"model":"claude-test","content":[{"type":"text","text":"Short "},{"type":"tool_use"},{"type":"text","text":"summary."}],
+				"usage":{"input_tokens":120,"output_tokens":15}}`,
+			want: Completion{Text: "Short summary.", Model: "claude-test", PromptTokens: 120, CompletionTokens: 15},
+		},
+		{
+			name:    "error body",
+			status:  http.StatusBadRequest,
+			body:    `{"type":"error","error":{"type":"invalid_request_error","message":"max_tokens is too large"}}`,
+			wantErr: "invalid_request_error: max_tokens is too large",
+		},
+		{
+			name:    "non-200 status",
+			status:  http.StatusBadGateway,
+			body:    `<html>Bad Gateway</html>`,
+			wantErr: "status code 502",
+		},
+		{
+			name:    "non-200 status with a JSON body",
+			status:  http.StatusServiceUnavailable,
+			body:    `{}`,
+			wantErr: "unexpected anthropic status code: 503",
+		},
+		{
+			name:    "no text",
+			status:  http.StatusOK,
+			body:    `{"model":"claude-test","content":[]}`,
+			wantErr: "no text",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/messages" {
+					t.Errorf("path = %q, want /v1/messages", r.URL.Path)
+				}
+
+				if r.Header.Get("X-Api-Key") != "key" || r.Header.Get("Anthropic-Version") != anthropicVersion {
+					t.Errorf("unexpected headers: %v", r.Header)
+				}
+
+				var params struct {
+					Model     string `json:"model"`
+					MaxTokens int    `json:"max_tokens"`
+					System    string `json:"system"`
+					Messages  []struct {
+						Role    string `json:"role"`
+						Content string `json:"content"`
+					} `json:"messages"`
+				}
+
+				if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+					t.Errorf("decode request: %v", err)
+				}
+
+				if params.Model != "claude-test" || params.MaxTokens != 100 || params.System != "system" ||
+					len(params.Messages) != 1 || params.Messages[0].Content != "user" {
+					t.Errorf("unexpected request: %+v", params)
+				}
+
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			backend := NewAnthropicBackend("key", srv.URL+"/", "claude-test", srv.Client())
+
+			got, err := backend.Complete(context.Background(), Request{System: "system", User: "user", MaxTokens: 100})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want it to contain %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("completion = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package summary
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Breaker is a circuit breaker that stops sending requests to a backend that keeps failing.
+// After the given number of consecutive failures the breaker opens and refuses requests for the cooldown.
+// Once the cooldown has passed, a single trial request is let through: if it succeeds, the breaker closes,
+// otherwise it stays open for another cooldown.
+type Breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	trial     bool
+}
+
+// NewBreaker initializes a new instance of Breaker.
+// Parameters:
+// - threshold: The number of consecutive failures that open the breaker; 0 disables the breaker.
+// - cooldown: The time requests are refused for once the breaker opens.
+// Returns:
+// - An initialized Breaker instance.
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{threshold: threshold, cooldown: cooldown}
+}
+
+// Allow reports whether a request may be sent. When the cooldown of an open breaker has passed,
+// it allows a single trial request, which must be followed by Success, Failure or Abort.
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openUntil.IsZero() {
+		return true
+	}
+
+	if b.trial || time.Now().Before(b.openUntil) {
+		return false
+	}
+
+	b.trial = true
+
+	return true
+}
+
+// Success records a successful request, closing the breaker.
+// It reports whether the breaker was open.
+func (b *Breaker) Success() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wasOpen := !b.openUntil.IsZero()
+
+	b.failures = 0
+	b.openUntil = time.Time{}
+	b.trial = false
+
+	return wasOpen
+}
+
+// Failure records a failed request. It reports whether the failure opened the closed breaker;
+// a failed trial request keeps the breaker open without reporting it again.
+func (b *Breaker) Failure() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.threshold <= 0 {
+		return false
+	}
+
+	b.failures++
+
+	if b.trial {
+		b.trial = false
+		b.openUntil = time.Now().Add(b.cooldown)
+
+		return false
+	}
+
+	if !b.openUntil.IsZero() || b.failures < b.threshold {
+		return false
+	}
+
+	b.openUntil = time.Now().Add(b.cooldown)
+
+	return true
+}
+
+// Repeat records a failed request that repeats a failure already recorded, e.g. of another chunk of
+// the same summary. The count of consecutive failures is kept, but a failed trial request keeps the breaker open.
+func (b *Breaker) Repeat() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.trial {
+		b.trial = false
+		b.openUntil = time.Now().Add(b.cooldown)
+	}
+}
+
+// Abort records a request that ended without an outcome, e.g. because it was canceled by the caller,
+// so that another trial request may be sent.
+func (b *Breaker) Abort() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+}
+
+// failuresKey is the context key of the breakers that have recorded a failure of a single summary.
+type failuresKey struct{}
+
+// failures holds the breakers that have recorded a failure of the requests made for a single summary,
+// so that the concurrent requests for its chunks count as a single failure.
+type failures struct {
+	mu       sync.Mutex
+	breakers map[*Breaker]struct{}
+}
+
+// withFailures returns a copy of the context in which the failed requests of each breaker are counted once.
+func withFailures(ctx context.Context) context.Context {
+	return context.WithValue(ctx, failuresKey{}, &failures{breakers: make(map[*Breaker]struct{})})
+}
+
+// recordFailure records the failed request with the breaker and reports whether it opened the breaker.
+// Within a context returned by withFailures, only the first failure of each breaker is counted.
+func recordFailure(ctx context.Context, b *Breaker) bool {
+	f, ok := ctx.Value(failuresKey{}).(*failures)
+	if !ok {
+		return b.Failure()
+	}
+
+	f.mu.Lock()
+	_, repeated := f.breakers[b]
+	f.breakers[b] = struct{}{}
+	f.mu.Unlock()
+
+	if repeated {
+		b.Repeat()
+		return false
+	}
+
+	return b.Failure()
+}
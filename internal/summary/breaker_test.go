@@ -0,0 +1,126 @@
+package summary
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	const cooldown = 20 * time.Millisecond
+
+	// Steps are applied in order: "allow" and "deny" expect the result of Allow,
+	// "fail", "fail-open" expect the result of Failure, "succeed" and "succeed-open" the result of Success.
+	tests := []struct {
+		name      string
+		threshold int
+		steps     []string
+	}{
+		{
+			name:      "opens after the threshold",
+			threshold: 2,
+			steps:     []string{"allow", "fail", "allow", "fail-open", "deny"},
+		},
+		{
+			name:      "success resets the failures",
+			threshold: 2,
+			steps:     []string{"fail", "succeed", "fail", "allow"},
+		},
+		{
+			name:      "single trial after the cooldown",
+			threshold: 1,
+			steps:     []string{"fail-open", "deny", "wait", "allow", "deny"},
+		},
+		{
+			name:      "successful trial closes the breaker",
+			threshold: 1,
+			steps:     []string{"fail-open", "wait", "allow", "succeed-open", "allow", "allow"},
+		},
+		{
+			name:      "failed trial reopens without reporting",
+			threshold: 1,
+			steps:     []string{"fail-open", "wait", "allow", "fail", "deny", "wait", "allow"},
+		},
+		{
+			name:      "aborted trial lets another trial through",
+			threshold: 1,
+			steps:     []string{"fail-open", "wait", "allow", "deny", "abort", "allow"},
+		},
+		{
+			name:      "repeated failure of a trial reopens",
+			threshold: 1,
+			steps:     []string{"fail-open", "wait", "allow", "repeat", "deny"},
+		},
+		{
+			name:      "repeated failures do not count",
+			threshold: 2,
+			steps:     []string{"fail", "repeat", "repeat", "allow", "fail-open"},
+		},
+		{
+			name:      "disabled",
+			threshold: 0,
+			steps:     []string{"fail", "fail", "fail", "allow"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBreaker(tt.threshold, cooldown)
+
+			for i, step := range tt.steps {
+				var ok bool
+
+				switch step {
+				case "allow":
+					ok = b.Allow()
+				case "deny":
+					ok = !b.Allow()
+				case "fail":
+					ok = !b.Failure()
+				case "fail-open":
+					ok = b.Failure()
+				case "succeed":
+					ok = !b.Success()
+				case "succeed-open":
+					ok = b.Success()
+				case "repeat":
+					b.Repeat()
+					ok = true
+				case "abort":
+					b.Abort()
+					ok = true
+				case "wait":
+					time.Sleep(cooldown + 5*time.Millisecond)
+					ok = true
+				}
+
+				if !ok {
+					t.Fatalf("step %d (%s) failed, steps: %v", i, step, tt.steps)
+				}
+			}
+		})
+	}
+}
+
+func TestRecordFailure(t *testing.T) {
+	b := NewBreaker(2, time.Minute)
+	ctx := withFailures(context.Background())
+
+	for i := 0; i < 5; i++ {
+		if recordFailure(ctx, b) {
+			t.Fatalf("failure %d of a single summary opened the breaker", i)
+		}
+	}
+
+	if !b.Allow() {
+		t.Fatal("the failures of a single summary must count once")
+	}
+
+	if !recordFailure(withFailures(context.Background()), b) {
+		t.Error("the failure of another summary must open the breaker")
+	}
+
+	if recordFailure(context.Background(), NewBreaker(2, time.Minute)) {
+		t.Error("a single failure without a summary must not open the breaker")
+	}
+}
@@ -0,0 +1,155 @@
+package summary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ErrBreakerOpen is returned for a backend skipped because its circuit breaker is open.
+var ErrBreakerOpen = errors.New("circuit breaker is open")
+
+// AlertFunc is called when the circuit breaker of a backend opens, with the model of the backend,
+// the error of the request that opened it and the time the backend is skipped for.
+type AlertFunc func(ctx context.Context, model string, err error, cooldown time.Duration)
+
+// Link is a backend of a Chain along with the time limit of its requests.
+type Link struct {
+	Backend Backend
+	// Timeout limits the time spent on a single request to the backend, or 0 for no limit of its own.
+	Timeout time.Duration
+}
+
+// chainLink is a link of a Chain with its circuit breaker.
+type chainLink struct {
+	Link
+	breaker *Breaker
+}
+
+// Chain is a Backend that sends requests to an ordered list of backends, e.g. a primary model followed
+// by a cheaper one, until one of them replies. Every backend has its own circuit breaker, so that
+// a backend that keeps failing is skipped for a cooldown instead of delaying every request.
+type Chain struct {
+	links []chainLink
+	alert AlertFunc
+}
+
+// NewChain initializes a new instance of Chain.
+// Parameters:
+// - links: The backends in the order they are tried, the first one being the primary backend.
+// - failures: The number of consecutive failures that open the circuit breaker of a backend; 0 disables the breakers.
+// - cooldown: The time a backend is skipped for once its circuit breaker opens.
+// Returns:
+// - An initialized Chain instance.
+func NewChain(links []Link, failures int, cooldown time.Duration) *Chain {
+	chain := &Chain{links: make([]chainLink, 0, len(links))}
+
+	for _, link := range links {
+		chain.links = append(chain.links, chainLink{Link: link, breaker: NewBreaker(failures, cooldown)})
+	}
+
+	return chain
+}
+
+// SetAlert sets the function called when the circuit breaker of a backend opens.
+func (c *Chain) SetAlert(alert AlertFunc) {
+	c.alert = alert
+}
+
+// Model returns the name of the model of the primary backend.
+func (c *Chain) Model() string {
+	if len(c.links) == 0 {
+		return ""
+	}
+
+	return c.links[0].Backend.Model()
+}
+
+// Timeout returns the longest time a single request may take, when every backend is tried in turn,
+// or 0 if a backend has no time limit of its own.
+func (c *Chain) Timeout() time.Duration {
+	var timeout time.Duration
+
+	for _, link := range c.links {
+		if link.Timeout <= 0 {
+			return 0
+		}
+
+		timeout += link.Timeout
+	}
+
+	return timeout
+}
+
+// Models returns the names of the models of all backends, in the order they are tried.
+func (c *Chain) Models() []string {
+	models := make([]string, 0, len(c.links))
+
+	for _, link := range c.links {
+		models = append(models, link.Backend.Model())
+	}
+
+	return models
+}
+
+// Complete returns the reply of the first backend that replies to the request. Backends whose
+// circuit breaker is open are skipped. Replies of backends other than the primary one are marked as Backup.
+// The failed requests made for a single summary, e.g. for its chunks, count as a single failure of a backend.
+func (c *Chain) Complete(ctx context.Context, req Request) (Completion, error) {
+	errs := make([]error, 0, len(c.links))
+
+	for i, link := range c.links {
+		model := link.Backend.Model()
+
+		if !link.breaker.Allow() {
+			errs = append(errs, fmt.Errorf("%s: %w", model, ErrBreakerOpen))
+			continue
+		}
+
+		completion, err := c.complete(ctx, link, req)
+		if err == nil {
+			if link.breaker.Success() {
+				log.Printf("[INFO] language model %s has recovered", model)
+			}
+
+			completion.Backup = i > 0
+
+			return completion, nil
+		}
+
+		if ctx.Err() != nil {
+			link.breaker.Abort()
+			return Completion{}, err
+		}
+
+		if recordFailure(ctx, link.breaker) {
+			log.Printf("[WARN] language model %s keeps failing and is skipped for %s: %v", model, link.breaker.cooldown, err)
+
+			if c.alert != nil {
+				go c.alert(context.WithoutCancel(ctx), model, err, link.breaker.cooldown)
+			}
+		}
+
+		if i < len(c.links)-1 {
+			log.Printf("[WARN] language model %s failed, trying the next one: %v", model, err)
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", model, err))
+	}
+
+	return Completion{}, errors.Join(errs...)
+}
+
+// complete sends the request to the backend of the link within its timeout.
+func (c *Chain) complete(ctx context.Context, link chainLink, req Request) (Completion, error) {
+	if link.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, link.Timeout)
+		defer cancel()
+	}
+
+	return link.Backend.Complete(ctx, req)
+}
@@ -0,0 +1,31 @@
+package summary
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChainTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeouts []time.Duration
+		want     time.Duration
+	}{
+		{"single backend", []time.Duration{time.Minute}, time.Minute},
+		{"backups", []time.Duration{time.Minute, 20 * time.Second, 2 * time.Minute}, 3*time.Minute + 20*time.Second},
+		{"backend without a limit", []time.Duration{time.Minute, 0}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			links := make([]Link, 0, len(tt.timeouts))
+			for _, timeout := range tt.timeouts {
+				links = append(links, Link{Backend: &echoBackend{}, Timeout: timeout})
+			}
+
+			if got := NewChain(links, 0, 0).Timeout(); got != tt.want {
+				t.Errorf("Timeout() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
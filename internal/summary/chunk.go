@@ -0,0 +1,293 @@
+package summary
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/kirinyoku/echo-wire-bot/internal/lang"
+)
+
+const (
+	// unitsPerToken is the number of units a token is counted in when estimating the size of text.
+	// ASCII characters count as one unit, as English text averages about four characters per token.
+	unitsPerToken = 4
+	// summaryMaxTokens is the maximum number of tokens of a summary.
+	summaryMaxTokens = 1024
+	// chunkSummaryMaxTokens is the maximum number of tokens of the summary of a chunk.
+	chunkSummaryMaxTokens = 512
+	// minChunkTokens is the minimum size of a chunk, used when the context window leaves less room for the text.
+	minChunkTokens = 256
+	// maxReduceRounds limits how many times the summaries of chunks are chunked and summarized again.
+	maxReduceRounds = 3
+	// maxConcurrentChunks is the maximum number of chunks of an article summarized at the same time.
+	maxConcurrentChunks = 4
+)
+
+// chunkPrompt is the system prompt used to summarize a chunk of a long article.
+const chunkPrompt = "The text is a part of a longer news article. Summarize the key facts of this part, " +
+	"keeping names, numbers and quotes. Reply with the summary only, in the language of the text."
+
+// countTokens estimates the number of tokens of the text. The estimate is conservative for
+// non-Latin scripts, which tokenizers split into more tokens per character than English.
+func countTokens(text string) int {
+	units := 0
+
+	for _, r := range text {
+		units += tokenUnits(r)
+	}
+
+	return (units + unitsPerToken - 1) / unitsPerToken
+}
+
+// tokenUnits returns the estimated size of the character in units of a quarter of a token.
+// Other scripts than ASCII, e.g. Cyrillic or accented Latin, take about two characters per token,
+// and CJK characters about a token each.
+func tokenUnits(r rune) int {
+	switch {
+	case r < utf8.RuneSelf:
+		return 1
+	case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul):
+		return unitsPerToken
+	default:
+		return 2
+	}
+}
+
+// cutTokens splits the text after its longest prefix that is estimated at no more than the given number of tokens.
+func cutTokens(text string, tokens int) (string, string) {
+	var (
+		limit = tokens * unitsPerToken
+		units int
+	)
+
+	for i, r := range text {
+		if units += tokenUnits(r); units > limit {
+			return text[:i], text[i:]
+		}
+	}
+
+	return text, ""
+}
+
+// chunkBudget returns the maximum number of tokens of the text sent with the prompt in a single request,
+// or 0 if the context window of the model is unknown and the text is never chunked.
+// The text sent to a Chain must fit the smallest context window of its models.
+func (s *LLMSummarizer) chunkBudget(prompt string) int {
+	models := []string{s.backend.Model()}
+	if chain, ok := s.backend.(*Chain); ok {
+		models = chain.Models()
+	}
+
+	window := 0
+
+	for _, model := range models {
+		modelWindow, ok := s.contextTokens[model]
+		if !ok {
+			modelWindow = s.defaultContextTokens
+		}
+
+		if modelWindow > 0 && (window == 0 || modelWindow < window) {
+			window = modelWindow
+		}
+	}
+
+	if window <= 0 {
+		return 0
+	}
+
+	return max(window-summaryMaxTokens-countTokens(prompt), minChunkTokens)
+}
+
+// summarize summarizes the text with the system prompt in the language with the given ISO 639-1 code,
+// checking that the summary is written in the expected language, if any.
+// Text over the chunk budget is split into chunks on paragraph boundaries, the chunks are summarized
+// concurrently and their summaries are summarized together. All the requests made for the summary
+// count as a single failure of a backend of a Chain.
+func (s *LLMSummarizer) summarize(ctx context.Context, text, prompt, code, expected string) (Result, error) {
+	var result Result
+
+	ctx = withFailures(ctx)
+
+	text, err := s.reduce(ctx, text, prompt, &result)
+	if err != nil {
+		return Result{}, err
+	}
+
+	if err := s.finish(ctx, text, prompt, code, expected, &result); err != nil {
+		return Result{}, err
+	}
+
+	return result, nil
+}
+
+// reduce shortens the text to fit the chunk budget of the prompt by summarizing its chunks,
+// adding their usage to the result. Text that still does not fit after maxReduceRounds is cut.
+func (s *LLMSummarizer) reduce(ctx context.Context, text, prompt string, result *Result) (string, error) {
+	budget := s.chunkBudget(prompt)
+	if budget == 0 {
+		return text, nil
+	}
+
+	for round := 0; countTokens(text) > budget; round++ {
+		if round == maxReduceRounds {
+			text, _ = cutTokens(text, budget)
+			return text, nil
+		}
+
+		summaries, err := s.summarizeChunks(ctx, splitChunks(text, budget), min(chunkSummaryMaxTokens, budget/4), result)
+		if err != nil {
+			return "", err
+		}
+
+		text = strings.Join(summaries, "\n")
+	}
+
+	return text, nil
+}
+
+// summarizeChunks summarizes up to maxConcurrentChunks chunks concurrently and adds their usage to the result.
+// The summaries are returned in the order of the chunks.
+func (s *LLMSummarizer) summarizeChunks(ctx context.Context, chunks []string, maxTokens int, result *Result) ([]string, error) {
+	var (
+		summaries   = make([]string, len(chunks))
+		completions = make([]Completion, len(chunks))
+		errs        = make([]error, len(chunks))
+		sem         = make(chan struct{}, maxConcurrentChunks)
+		wg          sync.WaitGroup
+	)
+
+	for i, chunk := range chunks {
+		sem <- struct{}{}
+
+		wg.Add(1)
+
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			completions[i], errs[i] = s.complete(ctx, Request{
+				System:    chunkPrompt,
+				User:      chunk,
+				MaxTokens: maxTokens,
+				Purpose:   PurposeChunk,
+			})
+		}()
+	}
+
+	wg.Wait()
+
+	for i, completion := range completions {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+
+		result.add(completion)
+		summaries[i] = completion.Text
+	}
+
+	return summaries, nil
+}
+
+// add adds the usage and the latency of the completion to the result.
+func (r *Result) add(completion Completion) {
+	r.PromptTokens += completion.PromptTokens
+	r.CompletionTokens += completion.CompletionTokens
+	r.Latency += completion.Latency
+	r.Backup = r.Backup || completion.Backup
+}
+
+// splitChunks splits the text into chunks of at most budget tokens. Chunks are cut on paragraph
+// boundaries; paragraphs over the budget are cut on sentence boundaries, and sentences over the
+// budget are cut at the budget.
+func splitChunks(text string, budget int) []string {
+	var (
+		chunks []string
+		chunk  []string
+		size   int
+	)
+
+	flush := func() {
+		if len(chunk) > 0 {
+			chunks = append(chunks, strings.Join(chunk, "\n"))
+			chunk, size = nil, 0
+		}
+	}
+
+	for _, part := range splitParts(text, budget) {
+		tokens := countTokens(part) + 1
+		if size+tokens > budget {
+			flush()
+		}
+
+		chunk = append(chunk, part)
+		size += tokens
+	}
+
+	flush()
+
+	return chunks
+}
+
+// splitParts splits the text into non-empty paragraphs that fit the budget, splitting larger paragraphs.
+func splitParts(text string, budget int) []string {
+	var parts []string
+
+	for _, paragraph := range strings.Split(text, "\n") {
+		paragraph = strings.TrimSpace(paragraph)
+
+		switch {
+		case paragraph == "":
+		case countTokens(paragraph) < budget:
+			parts = append(parts, paragraph)
+		default:
+			parts = append(parts, splitParagraph(paragraph, budget)...)
+		}
+	}
+
+	return parts
+}
+
+// splitParagraph splits the paragraph into parts of whole sentences that fit the budget.
+func splitParagraph(paragraph string, budget int) []string {
+	var (
+		parts   []string
+		current strings.Builder
+	)
+
+	flush := func() {
+		if current.Len() > 0 {
+			parts = append(parts, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, sentence := range lang.Sentences(paragraph, lang.Detect(paragraph)) {
+		for countTokens(sentence) >= budget {
+			flush()
+
+			var part string
+
+			part, sentence = cutTokens(sentence, max(budget-1, 1))
+			parts = append(parts, part)
+		}
+
+		if current.Len() > 0 && countTokens(current.String()+" "+sentence) >= budget {
+			flush()
+		}
+
+		if current.Len() > 0 {
+			current.WriteString(" ")
+		}
+
+		current.WriteString(sentence)
+	}
+
+	flush()
+
+	return parts
+}
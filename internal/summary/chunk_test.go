@@ -0,0 +1,254 @@
+package summary
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// echoBackend replies with the text it is given, or with its reply if set, and counts the requests.
+type echoBackend struct {
+	reply    string
+	requests atomic.Int64
+}
+
+func (b *echoBackend) Complete(_ context.Context, req Request) (Completion, error) {
+	b.requests.Add(1)
+
+	if b.reply != "" {
+		return Completion{Text: b.reply, PromptTokens: 10, CompletionTokens: 1}, nil
+	}
+
+	return Completion{Text: req.User, PromptTokens: 10, CompletionTokens: 10}, nil
+}
+
+func (b *echoBackend) Model() string {
+	return "echo"
+}
+
+func TestCountTokens(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want int
+	}{
+		{"empty", "", 0},
+		{"ascii", "abcd", 1},
+		{"ascii rounded up", "abcde", 2},
+		{"cyrillic", "Привет", 3},
+		{"accented latin", "éèêë", 2},
+		{"cjk", "日本語", 3},
+		{"mixed", "Hello, мир", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countTokens(tt.text); got != tt.want {
+				t.Errorf("countTokens(%q) = %d, want %d", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCutTokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		tokens   int
+		wantHead string
+		wantTail string
+	}{
+		{"ascii", "abcdefgh", 1, "abcd", "efgh"},
+		{"cyrillic", "Привет", 2, "Прив", "ет"},
+		{"cjk", "日本語", 2, "日本", "語"},
+		{"fits", "abc", 5, "abc", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			head, tail := cutTokens(tt.text, tt.tokens)
+			if head != tt.wantHead || tail != tt.wantTail {
+				t.Errorf("cutTokens(%q, %d) = %q, %q, want %q, %q", tt.text, tt.tokens, head, tail, tt.wantHead, tt.wantTail)
+			}
+		})
+	}
+}
+
+func TestSplitParagraph(t *testing.T) {
+	tests := []struct {
+		name      string
+		paragraph string
+		budget    int
+		want      []string
+	}{
+		{
+			name:      "sentences",
+			paragraph: "One two. Three four. Five six.",
+			budget:    5,
+			want:      []string{"One two.", "Three four.", "Five six."},
+		},
+		{
+			name:      "sentences together",
+			paragraph: "One two. Three four. Five six.",
+			budget:    6,
+			want:      []string{"One two. Three four.", "Five six."},
+		},
+		{
+			name:      "long sentence",
+			paragraph: "abcdefghijklmnopqrstuvwxyz",
+			budget:    3,
+			want:      []string{"abcdefgh", "ijklmnop", "qrstuvwx", "yz"},
+		},
+		{
+			name:      "long cyrillic sentence",
+			paragraph: "абвгдеёжзийклмн",
+			budget:    3,
+			want:      []string{"абвг", "деёж", "зийк", "лмн"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitParagraph(tt.paragraph, tt.budget)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitParagraph(%q, %d) = %q, want %q", tt.paragraph, tt.budget, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitParts(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		budget int
+		want   []string
+	}{
+		{
+			name:   "paragraphs",
+			text:   "First.\n\n  Second.  \n",
+			budget: 100,
+			want:   []string{"First.", "Second."},
+		},
+		{
+			name:   "paragraph over the budget",
+			text:   "Short.\nOne two. Three four. Five six.",
+			budget: 6,
+			want:   []string{"Short.", "One two. Three four.", "Five six."},
+		},
+		{
+			name:   "empty",
+			text:   "\n \n",
+			budget: 10,
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitParts(tt.text, tt.budget)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitParts(%q, %d) = %q, want %q", tt.text, tt.budget, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitChunks(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		budget int
+		want   []string
+	}{
+		{
+			name:   "fits",
+			text:   "aaaa\nbbbb",
+			budget: 10,
+			want:   []string{"aaaa\nbbbb"},
+		},
+		{
+			name:   "paragraph boundaries",
+			text:   strings.Repeat("a", 12) + "\n" + strings.Repeat("b", 12) + "\n" + strings.Repeat("c", 12),
+			budget: 10,
+			want:   []string{strings.Repeat("a", 12) + "\n" + strings.Repeat("b", 12), strings.Repeat("c", 12)},
+		},
+		{
+			name:   "cyrillic counts more than latin",
+			text:   strings.Repeat("ж", 12) + "\n" + strings.Repeat("з", 12),
+			budget: 10,
+			want:   []string{strings.Repeat("ж", 12), strings.Repeat("з", 12)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitChunks(tt.text, tt.budget)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitChunks(%q, %d) = %q, want %q", tt.text, tt.budget, got, tt.want)
+			}
+
+			for _, chunk := range got {
+				if tokens := countTokens(chunk); tokens > tt.budget {
+					t.Errorf("chunk %q has %d tokens, want at most %d", chunk, tokens, tt.budget)
+				}
+			}
+		})
+	}
+}
+
+func TestLLMSummarizerReduce(t *testing.T) {
+	// Each paragraph is 80 tokens, so a chunk holds three paragraphs and the text is split into seven chunks.
+	paragraph := strings.Repeat("Word word word. ", 20)
+	text := strings.Repeat(paragraph+"\n", 20)
+
+	tests := []struct {
+		name         string
+		reply        string
+		wantRequests int64
+		want         string
+	}{
+		{
+			name:         "chunks are summarized",
+			reply:        "Summary.",
+			wantRequests: 7,
+			want:         strings.TrimSuffix(strings.Repeat("Summary.\n", 7), "\n"),
+		},
+		{
+			name:         "text is cut after the maximum rounds",
+			wantRequests: maxReduceRounds * 7,
+			want:         strings.TrimSuffix(strings.Repeat(strings.TrimSpace(paragraph)+"\n", 20), "\n")[:minChunkTokens*unitsPerToken],
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := &echoBackend{reply: tt.reply}
+
+			// The context window is smaller than a summary, so the chunk budget is minChunkTokens.
+			s := New(backend, "Summarize.")
+			s.defaultContextTokens = 1
+
+			var result Result
+
+			got, err := s.reduce(context.Background(), text, s.prompt, &result)
+			if err != nil {
+				t.Fatalf("reduce() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("reduce() = %q, want %q", got, tt.want)
+			}
+
+			if requests := backend.requests.Load(); requests != tt.wantRequests {
+				t.Errorf("reduce() made %d requests, want %d", requests, tt.wantRequests)
+			}
+
+			if want := int(tt.wantRequests) * 10; result.PromptTokens != want {
+				t.Errorf("result.PromptTokens = %d, want %d", result.PromptTokens, want)
+			}
+		})
+	}
+}
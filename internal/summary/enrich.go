@@ -0,0 +1,142 @@
+package summary
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kirinyoku/echo-wire-bot/internal/lang"
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+)
+
+// maxTags is the maximum number of topic tags of an enrichment.
+const maxTags = 5
+
+// enrichPrompt is the system prompt used to enrich articles. The {topic} placeholder is replaced with
+// the topic of the channel and the {summary} placeholder with the instructions for the summary.
+const enrichPrompt = `You are a news editor. Analyze the news article and reply with a JSON object only, without comments, with the fields:
+"summary": a summary of the article following the summary instructions,
+"tags": up to 5 short topic tags in the language of the article,
+"relevance": a number from 0 to 1, how relevant the article is to the channel topic,
+"clickbait": true if the title exaggerates, misleads or withholds the news, otherwise false,
+"sentiment": a number from -1 (negative) to 1 (positive), the tone of the article.
+Channel topic: {topic}
+Summary instructions: {summary}`
+
+// Enrich analyzes the article and returns its summary, topic tags, relevance to the topic of the channel,
+// clickbait flag and sentiment. The reply of the model is validated: a reply without a summary, a relevance
+// or a clickbait flag is an error, and scores out of range are clamped.
+// Parameters:
+// - ctx: The context of the request.
+// - title: The title of the article.
+// - text: The text of the article; long texts are shortened in chunks as for Summarize.
+// - topic: The description of the topic of the channel, e.g. "European technology and startups".
+// - summaryPrompt: The prompt the summary is generated with, as for Summarize.
+// Returns:
+// - The enrichment, or an error if the operation fails.
+func (s *LLMSummarizer) Enrich(ctx context.Context, title, text, topic string, summaryPrompt Prompt) (models.Enrichment, error) {
+	if s.backend == nil {
+		return models.Enrichment{}, errDisabled
+	}
+
+	if topic == "" {
+		topic = "general news"
+	}
+
+	instructions := s.promptText(summaryPrompt)
+	if instructions == "" {
+		instructions = "a few sentences with the key facts"
+	}
+
+	prompt := strings.NewReplacer("{topic}", topic, "{summary}", instructions).Replace(enrichPrompt)
+
+	text, err := s.reduce(ctx, text, prompt, &Result{})
+	if err != nil {
+		return models.Enrichment{}, err
+	}
+
+	completion, err := s.complete(ctx, Request{
+		System:    prompt,
+		User:      "Title: " + title + "\n\n" + text,
+		MaxTokens: summaryMaxTokens + 256,
+		Purpose:   PurposeEnrichment,
+		JSON:      true,
+	})
+	if err != nil {
+		return models.Enrichment{}, err
+	}
+
+	enrichment, err := parseEnrichment(completion.Text)
+	if err != nil {
+		return models.Enrichment{}, err
+	}
+
+	code := summaryPrompt.Language
+	if code == "" {
+		code = lang.Detect(text)
+	}
+
+	if enrichment.Summary = s.clean(enrichment.Summary, code); enrichment.Summary == "" {
+		return models.Enrichment{}, errEmptySummary
+	}
+
+	enrichment.PromptVersion = s.PromptVersion(summaryPrompt)
+
+	return enrichment, nil
+}
+
+// parseEnrichment parses and validates the JSON reply of the model to the enrichment prompt.
+func parseEnrichment(reply string) (models.Enrichment, error) {
+	start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return models.Enrichment{}, errors.New("no JSON object in model response")
+	}
+
+	var parsed struct {
+		Summary   string   `json:"summary"`
+		Tags      []string `json:"tags"`
+		Relevance *float64 `json:"relevance"`
+		Clickbait *bool    `json:"clickbait"`
+		Sentiment float64  `json:"sentiment"`
+	}
+
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &parsed); err != nil {
+		return models.Enrichment{}, fmt.Errorf("invalid JSON in model response: %w", err)
+	}
+
+	switch {
+	case strings.TrimSpace(parsed.Summary) == "":
+		return models.Enrichment{}, errors.New("no summary in model response")
+	case parsed.Relevance == nil:
+		return models.Enrichment{}, errors.New("no relevance in model response")
+	case parsed.Clickbait == nil:
+		return models.Enrichment{}, errors.New("no clickbait flag in model response")
+	}
+
+	enrichment := models.Enrichment{
+		Summary:   strings.TrimSpace(parsed.Summary),
+		Relevance: min(max(*parsed.Relevance, 0), 1),
+		Clickbait: *parsed.Clickbait,
+		Sentiment: min(max(parsed.Sentiment, -1), 1),
+	}
+
+	seen := make(map[string]struct{}, len(parsed.Tags))
+
+	for _, tag := range parsed.Tags {
+		tag = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+		if _, ok := seen[strings.ToLower(tag)]; ok || tag == "" {
+			continue
+		}
+
+		seen[strings.ToLower(tag)] = struct{}{}
+		enrichment.Tags = append(enrichment.Tags, tag)
+
+		if len(enrichment.Tags) == maxTags {
+			break
+		}
+	}
+
+	return enrichment, nil
+}
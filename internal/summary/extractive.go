@@ -0,0 +1,204 @@
+package summary
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/kirinyoku/echo-wire-bot/internal/lang"
+)
+
+const (
+	// damping is the damping factor of TextRank.
+	damping = 0.85
+	// maxIterations and convergence limit the iterations of TextRank.
+	maxIterations = 100
+	convergence   = 1e-4
+	// stemLength is the number of leading letters words are compared by, which roughly
+	// matches inflected forms of the same word.
+	stemLength = 6
+	// minSentenceWords is the minimum number of meaningful words of a sentence to be picked for a summary.
+	minSentenceWords = 3
+)
+
+// ExtractiveModel is the model name reported in the results of ExtractiveSummarizer.
+const ExtractiveModel = "extractive"
+
+// ExtractiveSummarizer summarizes text offline by picking its most central sentences with TextRank.
+type ExtractiveSummarizer struct {
+	sentences int
+}
+
+// NewExtractiveSummarizer initializes a new instance of ExtractiveSummarizer.
+// Parameters:
+// - sentences: The number of sentences in a summary; values below 1 are treated as 1.
+// Returns:
+// - An initialized ExtractiveSummarizer instance.
+func NewExtractiveSummarizer(sentences int) *ExtractiveSummarizer {
+	return &ExtractiveSummarizer{sentences: max(sentences, 1)}
+}
+
+// Summarize generates a summary of the given text from its own sentences.
+// Sentences are split according to the detected language of the text and
+// returned in their original order.
+// Parameters:
+// - ctx: The context of the request, unused since no request is sent.
+// - text: The input text to summarize.
+// - prompt: Unused, since the summary consists of the sentences of the text.
+// Returns:
+// - The summary, or an error if the text has no sentences.
+func (s *ExtractiveSummarizer) Summarize(_ context.Context, text string, _ Prompt) (Result, error) {
+	start := time.Now()
+	code := lang.Detect(text)
+
+	sentences := lang.Sentences(text, code)
+	if len(sentences) == 0 {
+		return Result{}, errors.New("no sentences to summarize")
+	}
+
+	if len(sentences) <= s.sentences {
+		return Result{Text: strings.Join(sentences, " "), Model: ExtractiveModel, Latency: time.Since(start)}, nil
+	}
+
+	words := make([][]string, len(sentences))
+	for i, sentence := range sentences {
+		words[i] = keywords(sentence, code)
+	}
+
+	scores := textRank(words)
+
+	candidates := make([]int, 0, len(sentences))
+	for i := range sentences {
+		if len(words[i]) >= minSentenceWords {
+			candidates = append(candidates, i)
+		}
+	}
+
+	if len(candidates) < s.sentences {
+		candidates = candidates[:0]
+		for i := range sentences {
+			candidates = append(candidates, i)
+		}
+	}
+
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return scores[candidates[a]] > scores[candidates[b]]
+	})
+
+	picked := candidates[:s.sentences]
+	sort.Ints(picked)
+
+	summary := make([]string, len(picked))
+	for i, index := range picked {
+		summary[i] = sentences[index]
+	}
+
+	return Result{Text: strings.Join(summary, " "), Model: ExtractiveModel, Latency: time.Since(start)}, nil
+}
+
+// keywords returns the stems of the meaningful words of the sentence.
+func keywords(sentence, code string) []string {
+	var stems []string
+
+	for _, word := range strings.FieldsFunc(strings.ToLower(sentence), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := []rune(word)
+		if len(runes) < 2 || lang.IsStopWord(code, word) {
+			continue
+		}
+
+		if len(runes) > stemLength {
+			runes = runes[:stemLength]
+		}
+
+		stems = append(stems, string(runes))
+	}
+
+	return stems
+}
+
+// textRank scores the sentences, given as their keywords, by how similar they are to the other sentences.
+func textRank(words [][]string) []float64 {
+	n := len(words)
+
+	sets := make([]map[string]struct{}, n)
+	for i, stems := range words {
+		sets[i] = make(map[string]struct{}, len(stems))
+		for _, stem := range stems {
+			sets[i][stem] = struct{}{}
+		}
+	}
+
+	weights := make([][]float64, n)
+	totals := make([]float64, n)
+
+	for i := range weights {
+		weights[i] = make([]float64, n)
+	}
+
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			w := similarity(sets[i], sets[j])
+			weights[i][j], weights[j][i] = w, w
+			totals[i] += w
+			totals[j] += w
+		}
+	}
+
+	scores := make([]float64, n)
+	for i := range scores {
+		scores[i] = 1
+	}
+
+	for iteration := 0; iteration < maxIterations; iteration++ {
+		next := make([]float64, n)
+		delta := 0.0
+
+		for i := 0; i < n; i++ {
+			rank := 0.0
+
+			for j := 0; j < n; j++ {
+				if weights[j][i] > 0 {
+					rank += weights[j][i] / totals[j] * scores[j]
+				}
+			}
+
+			next[i] = 1 - damping + damping*rank
+			delta = max(delta, math.Abs(next[i]-scores[i]))
+		}
+
+		scores = next
+
+		if delta < convergence {
+			break
+		}
+	}
+
+	return scores
+}
+
+// similarity returns the number of keywords the sentences share, normalized by their lengths.
+func similarity(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	var shared int
+
+	for stem := range a {
+		if _, ok := b[stem]; ok {
+			shared++
+		}
+	}
+
+	if shared == 0 {
+		return 0
+	}
+
+	return float64(shared) / (math.Log(float64(len(a)+1)) + math.Log(float64(len(b)+1)))
+}
@@ -0,0 +1,132 @@
+package summary
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTextRank(t *testing.T) {
+	tests := []struct {
+		name  string
+		words [][]string
+		// best is the index of the sentence expected to score highest, or -1 if all scores must be equal.
+		best int
+	}{
+		{
+			name: "central sentence",
+			words: [][]string{
+				{"electi", "parlia", "vote"},
+				{"electi", "parlia", "vote", "turnou", "result"},
+				{"turnou", "result", "record"},
+				{"weathe", "sunny"},
+			},
+			best: 1,
+		},
+		{
+			name:  "no shared words",
+			words: [][]string{{"alpha"}, {"beta"}, {"gamma"}},
+			best:  -1,
+		},
+		{
+			name:  "single sentence",
+			words: [][]string{{"alpha", "beta"}},
+			best:  -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scores := textRank(tt.words)
+			if len(scores) != len(tt.words) {
+				t.Fatalf("got %d scores for %d sentences", len(scores), len(tt.words))
+			}
+
+			if tt.best < 0 {
+				for i, score := range scores {
+					if score != scores[0] {
+						t.Errorf("score %d = %v, want %v like the others", i, score, scores[0])
+					}
+				}
+
+				return
+			}
+
+			for i, score := range scores {
+				if i != tt.best && score >= scores[tt.best] {
+					t.Errorf("score %d = %v, want it below the score %v of sentence %d", i, score, scores[tt.best], tt.best)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractiveSummarizerSummarize(t *testing.T) {
+	text := "The parliament held an election on Sunday. " +
+		"The election gave the ruling party a majority in parliament, with a record turnout. " +
+		"The turnout was a record for a parliament election. " +
+		"It was sunny in the capital."
+
+	tests := []struct {
+		name      string
+		text      string
+		sentences int
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "most central sentence",
+			text:      text,
+			sentences: 1,
+			want:      "The turnout was a record for a parliament election.",
+		},
+		{
+			name:      "original order",
+			text:      text,
+			sentences: 2,
+			want: "The election gave the ruling party a majority in parliament, with a record turnout. " +
+				"The turnout was a record for a parliament election.",
+		},
+		{
+			name:      "short text",
+			text:      "Only one sentence.",
+			sentences: 3,
+			want:      "Only one sentence.",
+		},
+		{
+			name:      "no sentences",
+			text:      "  ",
+			sentences: 3,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewExtractiveSummarizer(tt.sentences).Summarize(context.Background(), tt.text, Prompt{})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result.Text != tt.want {
+				t.Errorf("summary = %q, want %q", result.Text, tt.want)
+			}
+
+			if result.Model != ExtractiveModel {
+				t.Errorf("model = %q, want %q", result.Model, ExtractiveModel)
+			}
+
+			if strings.Count(result.Text, ".") > tt.sentences {
+				t.Errorf("summary has more than %d sentences: %q", tt.sentences, result.Text)
+			}
+		})
+	}
+}
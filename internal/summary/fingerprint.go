@@ -0,0 +1,25 @@
+package summary
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Fingerprint returns the hex-encoded SHA-256 hash of the normalized text, so that copies of
+// the same text that differ only in Unicode composition or whitespace have the same fingerprint.
+func Fingerprint(text string) string {
+	normalized := strings.Join(strings.Fields(norm.NFC.String(text)), " ")
+	sum := sha256.Sum256([]byte(normalized))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// promptVersion returns a short hash identifying the prompts used for summaries.
+func promptVersion(prompts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(prompts, "\x00")))
+
+	return hex.EncodeToString(sum[:6])
+}
@@ -0,0 +1,113 @@
+package summary
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter bounds the number of requests and tokens sent to a language model per minute.
+// Both limits are token buckets refilled continuously, so short bursts up to the limit are allowed.
+type Limiter struct {
+	mu       sync.Mutex
+	requests bucket
+	tokens   bucket
+}
+
+// bucket is a token bucket holding up to limit units, refilled at limit units per minute.
+type bucket struct {
+	limit     float64
+	available float64
+	updatedAt time.Time
+}
+
+// NewLimiter creates a new Limiter.
+// Parameters:
+// - requestsPerMinute: The maximum number of requests per minute, 0 for no limit.
+// - tokensPerMinute: The maximum number of tokens per minute, 0 for no limit.
+// Returns:
+// - An initialized Limiter instance.
+func NewLimiter(requestsPerMinute, tokensPerMinute int) *Limiter {
+	now := time.Now()
+
+	return &Limiter{
+		requests: bucket{limit: float64(requestsPerMinute), available: float64(requestsPerMinute), updatedAt: now},
+		tokens:   bucket{limit: float64(tokensPerMinute), available: float64(tokensPerMinute), updatedAt: now},
+	}
+}
+
+// Wait blocks until a request using the given number of tokens is allowed, or the context is done.
+// Requests using more tokens than the per-minute limit wait for the full limit.
+func (l *Limiter) Wait(ctx context.Context, tokens int) error {
+	for {
+		l.mu.Lock()
+
+		now := time.Now()
+		l.requests.refill(now)
+		l.tokens.refill(now)
+
+		delay := max(l.requests.delay(1), l.tokens.delay(float64(tokens)))
+		if delay == 0 {
+			l.requests.take(1)
+			l.tokens.take(float64(tokens))
+			l.mu.Unlock()
+
+			return nil
+		}
+
+		l.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Adjust corrects the tokens taken by the last request by the difference
+// between the tokens it actually used and the tokens it waited for.
+func (l *Limiter) Adjust(tokens int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens.refill(time.Now())
+	l.tokens.take(float64(tokens))
+}
+
+// refill adds the units accumulated since the last update.
+func (b *bucket) refill(now time.Time) {
+	if b.limit == 0 {
+		return
+	}
+
+	b.available = min(b.limit, b.available+now.Sub(b.updatedAt).Minutes()*b.limit)
+	b.updatedAt = now
+}
+
+// delay returns the time until the bucket holds the given number of units.
+func (b *bucket) delay(units float64) time.Duration {
+	if b.limit == 0 {
+		return 0
+	}
+
+	units = min(units, b.limit)
+	if b.available >= units {
+		return 0
+	}
+
+	return max(time.Duration((units-b.available)/b.limit*float64(time.Minute)), time.Millisecond)
+}
+
+// take removes the units from the bucket, or returns them if negative. The bucket may go
+// negative after an adjustment, which delays the following requests.
+func (b *bucket) take(units float64) {
+	if b.limit == 0 {
+		return
+	}
+
+	b.available = min(max(b.available-min(units, b.limit), -b.limit), b.limit)
+}
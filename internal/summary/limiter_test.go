@@ -0,0 +1,152 @@
+package summary
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBucket(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		limit     float64
+		available float64
+		elapsed   time.Duration
+		take      float64
+		// wantAvailable is the number of units after the refill and the take.
+		wantAvailable float64
+		// wantDelay is the delay for a single unit after the refill and the take.
+		wantDelay time.Duration
+	}{
+		{
+			name:          "full bucket",
+			limit:         60,
+			available:     60,
+			take:          1,
+			wantAvailable: 59,
+		},
+		{
+			name:          "refill after half a minute",
+			limit:         60,
+			available:     0,
+			elapsed:       30 * time.Second,
+			take:          10,
+			wantAvailable: 20,
+		},
+		{
+			name:          "refill is capped at the limit",
+			limit:         60,
+			available:     50,
+			elapsed:       time.Hour,
+			wantAvailable: 60,
+		},
+		{
+			name:          "empty bucket delays",
+			limit:         60,
+			available:     0,
+			wantAvailable: 0,
+			wantDelay:     time.Second,
+		},
+		{
+			name:          "take is capped at the limit",
+			limit:         60,
+			available:     60,
+			take:          1000,
+			wantAvailable: 0,
+			wantDelay:     time.Second,
+		},
+		{
+			name:          "negative take returns units",
+			limit:         60,
+			available:     10,
+			take:          -20,
+			wantAvailable: 30,
+		},
+		{
+			name:          "going negative delays the following requests",
+			limit:         60,
+			available:     10,
+			take:          40,
+			wantAvailable: -30,
+			wantDelay:     31 * time.Second,
+		},
+		{
+			name:          "negative balance is capped at minus the limit",
+			limit:         60,
+			available:     -50,
+			take:          60,
+			wantAvailable: -60,
+			wantDelay:     61 * time.Second,
+		},
+		{
+			name:          "no limit",
+			limit:         0,
+			take:          1000,
+			wantAvailable: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := bucket{limit: tt.limit, available: tt.available, updatedAt: start}
+
+			b.refill(start.Add(tt.elapsed))
+			b.take(tt.take)
+
+			if b.available != tt.wantAvailable {
+				t.Errorf("available = %v, want %v", b.available, tt.wantAvailable)
+			}
+
+			if delay := b.delay(1); delay != tt.wantDelay {
+				t.Errorf("delay = %v, want %v", delay, tt.wantDelay)
+			}
+		})
+	}
+}
+
+func TestLimiterAdjust(t *testing.T) {
+	l := NewLimiter(0, 600)
+
+	if err := l.Wait(context.Background(), 100); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The request used 800 tokens instead of 100, taking the bucket below zero.
+	l.Adjust(700)
+
+	if l.tokens.available >= 0 {
+		t.Fatalf("available = %v, want it below zero", l.tokens.available)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := l.Wait(ctx, 1); err == nil {
+		t.Error("a request after the bucket went negative must wait")
+	}
+
+	// Returned tokens make the following requests pass without waiting.
+	l.Adjust(-700)
+
+	if err := l.Wait(context.Background(), 100); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestLimiterWaitCanceled(t *testing.T) {
+	l := NewLimiter(1, 0)
+
+	if err := l.Wait(context.Background(), 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := l.Wait(ctx, 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,418 @@
+package summary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kirinyoku/echo-wire-bot/internal/lang"
+)
+
+// DefaultTranslatePrompt is the system prompt used to translate titles and summaries.
+// The {language} placeholder is replaced with the name of the target language.
+const DefaultTranslatePrompt = "Translate the text into {language}. Keep the meaning, tone, names and numbers. " +
+	"Reply with the translation only, without quotes or comments."
+
+// entitiesPrompt is the system prompt used to extract named entities for hashtags.
+const entitiesPrompt = "List up to 5 key named entities (people, organizations, places, products or events) " +
+	"mentioned in the text, most important first, one per line, in the language of the text. " +
+	"Reply with the list only, without numbering or comments."
+
+// urgencyPrompt is the system prompt used to score the urgency of news.
+const urgencyPrompt = "You are a news editor. Rate how urgent the news in the text is for readers, " +
+	"where 1 means breaking news that must be published immediately (disasters, attacks, major political events, " +
+	"market crashes) and 0 means news that can wait. Reply with a single number between 0 and 1 only."
+
+// listMarker matches the bullet or the number at the start of a list item.
+var listMarker = regexp.MustCompile(`^\s*(?:[-*•]|\d+[.)])\s*`)
+
+// errDisabled is returned when no backend is configured.
+var errDisabled = errors.New("summarizer is disabled")
+
+// Request is a request to a language model.
+type Request struct {
+	// System is the system prompt.
+	System string
+	// User is the user message, e.g. the text to summarize.
+	User      string
+	MaxTokens int
+	// Temperature is the sampling temperature, or 0 for the default of the provider.
+	Temperature float32
+	// Purpose is what the request is made for, recorded with its usage, e.g. PurposeSummary.
+	Purpose string
+	// JSON asks the model to reply with a JSON object, if the provider supports it.
+	JSON bool
+}
+
+// Completion is the reply of a language model.
+type Completion struct {
+	Text string
+	// Model is the model that generated the reply, as reported by the provider.
+	Model            string
+	PromptTokens     int
+	CompletionTokens int
+	// Latency is the time spent on the request, measured by LLMSummarizer.
+	Latency time.Duration
+	// Backup reports whether the reply was generated by a backup backend of a Chain instead of its primary one.
+	Backup bool
+}
+
+// Result is a summary along with the metadata of its generation.
+type Result struct {
+	Text             string
+	Model            string
+	PromptTokens     int
+	CompletionTokens int
+	// Latency is the time spent on generating the summary, without waiting for the limiter.
+	Latency time.Duration
+	// Fallback reports whether the summary was generated by the fallback instead of the language model.
+	Fallback bool
+	// Cached reports whether the summary was reused from the cache, without using any tokens.
+	Cached bool
+	// Backup reports whether any part of the summary was generated by a backup model of a Chain.
+	Backup bool
+	// PromptVersion is the version of the prompt the summary was generated with, see LLMSummarizer.PromptVersion.
+	// It is empty for summaries generated by the fallback.
+	PromptVersion string
+}
+
+// Prompt is a system prompt used to summarize a text in place of the default one.
+type Prompt struct {
+	// Text is the prompt with its variables already replaced, or empty for the default prompt.
+	Text string
+	// Version identifies the template the prompt was rendered from, e.g. "source:3:v2".
+	Version string
+	// Language is the ISO 639-1 code of the language the summary is expected in,
+	// or empty to expect the language detected in the text.
+	Language string
+	// AnyLanguage disables the language check of the summary, e.g. for prompts written by admins,
+	// which may ask for a language of their own. Language is still used to split the summary into sentences.
+	AnyLanguage bool
+}
+
+// Tokens returns the total number of tokens used for the summary.
+func (r Result) Tokens() int {
+	return r.PromptTokens + r.CompletionTokens
+}
+
+// Summarizer defines the interface for generating summaries of text content.
+type Summarizer interface {
+	Summarize(ctx context.Context, text string, prompt Prompt) (Result, error)
+}
+
+// Backend defines the interface for sending requests to a language model provider.
+type Backend interface {
+	// Complete returns the reply of the model to the request.
+	Complete(ctx context.Context, req Request) (Completion, error)
+	// Model returns the name of the model requests are sent to.
+	Model() string
+}
+
+// LLMSummarizer uses a language model to summarize, translate and analyze text.
+// The provider of the model is chosen by the Backend.
+type LLMSummarizer struct {
+	backend         Backend
+	prompt          string
+	translatePrompt string
+	fallback        Summarizer
+	limiter         *Limiter
+	accountant      *Accountant
+	requestTimeout  time.Duration
+
+	contextTokens        map[string]int
+	defaultContextTokens int
+
+	maxLength     int
+	checkLanguage bool
+}
+
+// New initializes a new instance of LLMSummarizer.
+// Parameters:
+// - backend: The backend of the language model provider, or nil to disable the summarizer.
+// - prompt: A system prompt to guide the model's response style.
+// Returns:
+// - An initialized LLMSummarizer instance.
+func New(backend Backend, prompt string) *LLMSummarizer {
+	return &LLMSummarizer{
+		backend:         backend,
+		prompt:          prompt,
+		translatePrompt: DefaultTranslatePrompt,
+	}
+}
+
+// SetTranslatePrompt replaces the system prompt used by Translate.
+// The {language} placeholder is replaced with the name of the target language.
+func (s *LLMSummarizer) SetTranslatePrompt(prompt string) {
+	if prompt != "" {
+		s.translatePrompt = prompt
+	}
+}
+
+// SetLimiter sets the limiter bounding the requests and tokens sent to the backend per minute.
+func (s *LLMSummarizer) SetLimiter(limiter *Limiter) {
+	s.limiter = limiter
+}
+
+// SetAccountant sets the accountant recording the usage of the backend and enforcing the budget caps.
+// Once a budget has been spent, summaries are generated by the fallback, unless the accountant skips them.
+func (s *LLMSummarizer) SetAccountant(accountant *Accountant) {
+	s.accountant = accountant
+}
+
+// SetContextTokens sets the context windows of models, in tokens, which limit the text summarized
+// in a single request. Models missing from the map use the default window; 0 disables chunking.
+func (s *LLMSummarizer) SetContextTokens(contextTokens map[string]int, defaultContextTokens int) {
+	s.contextTokens = contextTokens
+	s.defaultContextTokens = defaultContextTokens
+}
+
+// SetPostProcessing configures the post-processing of summaries. Summaries are shortened to maxLength
+// characters on a sentence boundary, 0 disables the limit. If checkLanguage is set, summaries of the
+// language model not written in the expected language are requested once more with a corrective prompt.
+func (s *LLMSummarizer) SetPostProcessing(maxLength int, checkLanguage bool) {
+	s.maxLength = maxLength
+	s.checkLanguage = checkLanguage
+}
+
+// SetRequestTimeout limits the time spent on a single request to the backend, including all the backends
+// tried by a Chain, see Chain.Timeout. 0 disables the limit.
+func (s *LLMSummarizer) SetRequestTimeout(timeout time.Duration) {
+	s.requestTimeout = timeout
+}
+
+// SetFallback sets the summarizer used when the backend is disabled or fails, e.g. an ExtractiveSummarizer.
+func (s *LLMSummarizer) SetFallback(fallback Summarizer) {
+	s.fallback = fallback
+}
+
+// Summarize generates a summary of the given text.
+// Text over the context window of the model is summarized in chunks, see SetContextTokens.
+// The summary is converted to plain text and shortened to complete sentences, see SetPostProcessing.
+// If the backend is disabled or fails, the summary is generated by the fallback, if any.
+// Parameters:
+// - ctx: The context of the request.
+// - text: The input text to summarize.
+// - prompt: The prompt to summarize the text with; an empty prompt means the default prompt given to New.
+// Returns:
+// - The summary with its metadata, or an error if the operation fails.
+func (s *LLMSummarizer) Summarize(ctx context.Context, text string, prompt Prompt) (Result, error) {
+	code := prompt.Language
+	if code == "" {
+		code = lang.Detect(text)
+	}
+
+	if s.backend == nil {
+		if s.fallback == nil {
+			return Result{}, errDisabled
+		}
+
+		return s.summarizeFallback(ctx, text, prompt, code)
+	}
+
+	expected := code
+	if prompt.AnyLanguage {
+		expected = ""
+	}
+
+	result, err := s.summarize(ctx, text, s.promptText(prompt), code, expected)
+	if err != nil {
+		if s.fallback == nil || ctx.Err() != nil {
+			return Result{}, err
+		}
+
+		if errors.Is(err, ErrBudgetExceeded) && s.accountant.skip {
+			return Result{}, err
+		}
+
+		log.Printf("[WARN] failed to summarize with the language model, using the fallback: %v", err)
+
+		return s.summarizeFallback(ctx, text, prompt, code)
+	}
+
+	result.PromptVersion = s.PromptVersion(prompt)
+
+	return result, nil
+}
+
+// summarizeFallback generates the summary of the text by the fallback and shortens it to the maximum length.
+func (s *LLMSummarizer) summarizeFallback(ctx context.Context, text string, prompt Prompt, code string) (Result, error) {
+	result, err := s.fallback.Summarize(ctx, text, prompt)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result.Text = s.clean(result.Text, code)
+	result.Fallback = true
+
+	return result, nil
+}
+
+// Model returns the name of the model summaries are generated with,
+// or an empty string if the summarizer has no backend.
+func (s *LLMSummarizer) Model() string {
+	if s.backend == nil {
+		return ""
+	}
+
+	return s.backend.Model()
+}
+
+// PromptVersion returns the version of the prompts summaries are generated with when given the prompt.
+// It is the version of the template of the prompt, if any, followed by the hash of the prompts actually sent,
+// e.g. "source:3:v2@1a2b3c4d5e6f". Summaries generated with the same model and prompt version are interchangeable.
+func (s *LLMSummarizer) PromptVersion(prompt Prompt) string {
+	version := promptVersion(s.promptText(prompt), chunkPrompt)
+	if prompt.Version == "" {
+		return version
+	}
+
+	return prompt.Version + "@" + version
+}
+
+// promptText returns the text of the prompt, or the default prompt if it is empty.
+func (s *LLMSummarizer) promptText(prompt Prompt) string {
+	if prompt.Text == "" {
+		return s.prompt
+	}
+
+	return prompt.Text
+}
+
+// Translate translates the given text into the language.
+// Parameters:
+// - ctx: The context of the request.
+// - text: The input text to translate.
+// - language: The English name of the target language, e.g. "German".
+// Returns:
+// - A string containing the translation, or an error if the operation fails.
+func (s *LLMSummarizer) Translate(ctx context.Context, text, language string) (string, error) {
+	completion, err := s.complete(ctx, Request{
+		System:    strings.ReplaceAll(s.translatePrompt, "{language}", language),
+		User:      text,
+		MaxTokens: 1024,
+		Purpose:   PurposeTranslation,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if completion.Text == "" {
+		return "", errors.New("empty translation in model response")
+	}
+
+	return completion.Text, nil
+}
+
+// Entities extracts the key named entities mentioned in the given text.
+// Parameters:
+// - ctx: The context of the request.
+// - text: The input text to extract entities from.
+// Returns:
+// - A slice of entity names, most important first, or an error if the operation fails.
+func (s *LLMSummarizer) Entities(ctx context.Context, text string) ([]string, error) {
+	completion, err := s.complete(ctx, Request{
+		System:    entitiesPrompt,
+		User:      text,
+		MaxTokens: 128,
+		Purpose:   PurposeEntities,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var entities []string
+
+	for _, line := range strings.Split(completion.Text, "\n") {
+		if entity := strings.TrimSpace(listMarker.ReplaceAllString(line, "")); entity != "" {
+			entities = append(entities, entity)
+		}
+	}
+
+	return entities, nil
+}
+
+// Urgency scores how urgent the news in the given text is.
+// Parameters:
+// - ctx: The context of the request.
+// - text: The title and the summary of an article.
+// Returns:
+// - The urgency from 0 (not urgent) to 1 (breaking news), or an error if the operation fails.
+func (s *LLMSummarizer) Urgency(ctx context.Context, text string) (float64, error) {
+	completion, err := s.complete(ctx, Request{
+		System:    urgencyPrompt,
+		User:      text,
+		MaxTokens: 8,
+		Purpose:   PurposeUrgency,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	urgency, err := strconv.ParseFloat(completion.Text, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected urgency in model response: %w", err)
+	}
+
+	return min(max(urgency, 0), 1), nil
+}
+
+// complete waits for the limiter, sends the request to the backend and returns the reply with trimmed text.
+func (s *LLMSummarizer) complete(ctx context.Context, req Request) (Completion, error) {
+	if s.backend == nil {
+		return Completion{}, errDisabled
+	}
+
+	if s.accountant != nil {
+		if err := s.accountant.Allow(ctx); err != nil {
+			return Completion{}, err
+		}
+	}
+
+	estimate := estimateTokens(req)
+
+	if s.limiter != nil {
+		if err := s.limiter.Wait(ctx, estimate); err != nil {
+			return Completion{}, err
+		}
+	}
+
+	if s.requestTimeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, s.requestTimeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+
+	completion, err := s.backend.Complete(ctx, req)
+	if err != nil {
+		return Completion{}, err
+	}
+
+	completion.Latency = time.Since(start)
+
+	if s.accountant != nil {
+		s.accountant.Record(ctx, req.Purpose, s.backend.Model(), completion)
+	}
+
+	if s.limiter != nil {
+		if used := completion.PromptTokens + completion.CompletionTokens; used > 0 {
+			s.limiter.Adjust(used - estimate)
+		}
+	}
+
+	completion.Text = strings.TrimSpace(completion.Text)
+
+	return completion, nil
+}
+
+// estimateTokens estimates the number of tokens the request uses at most.
+func estimateTokens(req Request) int {
+	return countTokens(req.System) + countTokens(req.User) + req.MaxTokens
+}
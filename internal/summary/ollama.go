@@ -0,0 +1,113 @@
+package summary
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DefaultOllamaBaseURL is the address of a local Ollama server.
+const DefaultOllamaBaseURL = "http://localhost:11434"
+
+// OllamaBackend sends requests to the chat API of an Ollama server.
+type OllamaBackend struct {
+	baseURL    string
+	model      string
+	httpClient *http.Client
+}
+
+// NewOllamaBackend initializes a new instance of OllamaBackend.
+// Parameters:
+// - baseURL: The address of the Ollama server, e.g. DefaultOllamaBaseURL.
+// - model: The model to be used for generating replies, e.g. "llama3.1".
+// - httpClient: The HTTP client used for requests.
+// Returns:
+// - An initialized OllamaBackend instance.
+func NewOllamaBackend(baseURL, model string, httpClient *http.Client) *OllamaBackend {
+	return &OllamaBackend{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		model:      model,
+		httpClient: httpClient,
+	}
+}
+
+// ollamaMessage is a message of the Ollama chat API.
+type ollamaMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Complete sends the request to the chat API and returns the reply.
+func (b *OllamaBackend) Complete(ctx context.Context, req Request) (Completion, error) {
+	options := map[string]any{"num_predict": req.MaxTokens}
+	if req.Temperature > 0 {
+		options["temperature"] = req.Temperature
+	}
+
+	params := map[string]any{
+		"model": b.model,
+		"messages": []ollamaMessage{
+			{Role: "system", Content: req.System},
+			{Role: "user", Content: req.User},
+		},
+		"stream":  false,
+		"options": options,
+	}
+
+	if req.JSON {
+		params["format"] = "json"
+	}
+
+	body, err := json.Marshal(params)
+	if err != nil {
+		return Completion{}, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, b.baseURL+"/api/chat", bytes.NewReader(body))
+	if err != nil {
+		return Completion{}, err
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.httpClient.Do(httpReq)
+	if err != nil {
+		return Completion{}, err
+	}
+	defer resp.Body.Close()
+
+	var chatResp struct {
+		Model           string        `json:"model"`
+		Message         ollamaMessage `json:"message"`
+		PromptEvalCount int           `json:"prompt_eval_count"`
+		EvalCount       int           `json:"eval_count"`
+		Error           string        `json:"error"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
+		return Completion{}, fmt.Errorf("unexpected ollama response with status code %d: %w", resp.StatusCode, err)
+	}
+
+	if chatResp.Error != "" {
+		return Completion{}, fmt.Errorf("ollama: %s", chatResp.Error)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return Completion{}, fmt.Errorf("unexpected ollama status code: %d", resp.StatusCode)
+	}
+
+	return Completion{
+		Text:             chatResp.Message.Content,
+		Model:            chatResp.Model,
+		PromptTokens:     chatResp.PromptEvalCount,
+		CompletionTokens: chatResp.EvalCount,
+	}, nil
+}
+
+// Model returns the name of the model requests are sent to.
+func (b *OllamaBackend) Model() string {
+	return b.model
+}
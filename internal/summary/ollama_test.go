@@ -0,0 +1,106 @@
+package summary
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOllamaBackendComplete(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    bool
+		status  int
+		body    string
+		want    Completion
+		wantErr string
+	}{
+		{
+			name:   "success",
+			status: http.StatusOK,
+			body:   `{"model":"llama3.1","message":{"role":"assistant","content":"Short summary."},"prompt_eval_count":80,"eval_count":12}`,
+			want:   Completion{Text: "Short summary.", Model: "llama3.1", PromptTokens: 80, CompletionTokens: 12},
+		},
+		{
+			name:   "json format",
+			json:   true,
+			status: http.StatusOK,
+			body:   `{"model":"llama3.1","message":{"role":"assistant","content":"{}"}}`,
+			want:   Completion{Text: "{}", Model: "llama3.1"},
+		},
+		{
+			name:    "error body",
+			status:  http.StatusNotFound,
+			body:    `{"error":"model \"llama3.1\" not found"}`,
+			wantErr: `ollama: model "llama3.1" not found`,
+		},
+		{
+			name:    "non-200 status",
+			status:  http.StatusBadGateway,
+			body:    `Bad Gateway`,
+			wantErr: "status code 502",
+		},
+		{
+			name:    "non-200 status with a JSON body",
+			status:  http.StatusInternalServerError,
+			body:    `{}`,
+			wantErr: "unexpected ollama status code: 500",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/chat" {
+					t.Errorf("path = %q, want /api/chat", r.URL.Path)
+				}
+
+				var params struct {
+					Model    string          `json:"model"`
+					Messages []ollamaMessage `json:"messages"`
+					Stream   bool            `json:"stream"`
+					Format   string          `json:"format"`
+					Options  map[string]any  `json:"options"`
+				}
+
+				if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+					t.Errorf("decode request: %v", err)
+				}
+
+				if params.Model != "llama3.1" || params.Stream || len(params.Messages) != 2 || params.Options["num_predict"] != float64(100) {
+					t.Errorf("unexpected request: %+v", params)
+				}
+
+				if tt.json != (params.Format == "json") {
+					t.Errorf("format = %q, json = %v", params.Format, tt.json)
+				}
+
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			backend := NewOllamaBackend(srv.URL, "llama3.1", srv.Client())
+
+			got, err := backend.Complete(context.Background(), Request{System: "system", User: "user", MaxTokens: 100, JSON: tt.json})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want it to contain %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("completion = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,95 +3,76 @@ package summary
 import (
 	"context"
 	"errors"
-	"fmt"
-	"log"
-	"strings"
-	"sync"
-	"time"
 
 	"github.com/sashabaranov/go-openai"
 )
 
-// OpenAISummarizer uses OpenAI's API to summarize text.
-type OpenAISummarizer struct {
-	client  *openai.Client
-	prompt  string
-	model   string
-	enabled bool
-	mu      sync.Mutex
+// OpenAIBackend sends requests to the OpenAI chat completions API,
+// or to any OpenAI-compatible endpoint such as vLLM or LM Studio.
+type OpenAIBackend struct {
+	client *openai.Client
+	model  string
 }
 
-// NewOpenAISummarizer initializes a new instance of OpenAISummarizer.
+// NewOpenAIBackend initializes a new instance of OpenAIBackend.
 // Parameters:
-// - apiKey: API key for authenticating with OpenAI.
-// - model: The model to be used for generating summaries.
-// - prompt: A system prompt to guide OpenAI's response style.
+// - apiKey: API key for authenticating with the endpoint; local endpoints may accept any key.
+// - baseURL: The base URL of an OpenAI-compatible endpoint, e.g. "http://localhost:8000/v1", or empty for OpenAI.
+// - model: The model to be used for generating replies.
 // Returns:
-// - An initialized OpenAISummarizer instance.
-func NewOpenAISummarizer(apiKey, model, prompt string) *OpenAISummarizer {
-	s := &OpenAISummarizer{
-		client: openai.NewClient(apiKey),
-		prompt: prompt,
-		model:  model,
+// - An initialized OpenAIBackend instance.
+func NewOpenAIBackend(apiKey, baseURL, model string) *OpenAIBackend {
+	config := openai.DefaultConfig(apiKey)
+	if baseURL != "" {
+		config.BaseURL = baseURL
 	}
 
-	log.Printf("openai summarizer is enabled: %v", apiKey != "")
-
-	if apiKey != "" {
-		s.enabled = true
+	return &OpenAIBackend{
+		client: openai.NewClientWithConfig(config),
+		model:  model,
 	}
-
-	return s
 }
 
-// Summarize generates a summary of the given text using OpenAI.
-// Parameters:
-// - text: The input text to summarize.
-// Returns:
-// - A string containing the summary, or an error if the operation fails.
-func (s *OpenAISummarizer) Summarize(text string) (string, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	if !s.enabled {
-		return "", fmt.Errorf("openai summarizer is disabled")
+// Complete sends the request as a chat completion and returns the reply.
+func (b *OpenAIBackend) Complete(ctx context.Context, req Request) (Completion, error) {
+	var format *openai.ChatCompletionResponseFormat
+	if req.JSON {
+		format = &openai.ChatCompletionResponseFormat{Type: openai.ChatCompletionResponseFormatTypeJSONObject}
 	}
 
-	request := openai.ChatCompletionRequest{
-		Model: s.model,
+	resp, err := b.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+		Model: b.model,
 		Messages: []openai.ChatCompletionMessage{
 			{
 				Role:    openai.ChatMessageRoleSystem,
-				Content: s.prompt,
+				Content: req.System,
 			},
 			{
 				Role:    openai.ChatMessageRoleUser,
-				Content: text,
+				Content: req.User,
 			},
 		},
-		MaxTokens:   1024,
-		Temperature: 1,
-		TopP:        1,
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
-	defer cancel()
-
-	resp, err := s.client.CreateChatCompletion(ctx, request)
+		MaxTokens:      req.MaxTokens,
+		Temperature:    req.Temperature,
+		ResponseFormat: format,
+	})
 	if err != nil {
-		return "", err
+		return Completion{}, err
 	}
 
 	if len(resp.Choices) == 0 {
-		return "", errors.New("no choices in openai response")
+		return Completion{}, errors.New("no choices in openai response")
 	}
 
-	rawSummary := strings.TrimSpace(resp.Choices[0].Message.Content)
-	if strings.HasSuffix(rawSummary, ".") {
-		return rawSummary, nil
-	}
-
-	sentences := strings.Split(rawSummary, ".")
+	return Completion{
+		Text:             resp.Choices[0].Message.Content,
+		Model:            resp.Model,
+		PromptTokens:     resp.Usage.PromptTokens,
+		CompletionTokens: resp.Usage.CompletionTokens,
+	}, nil
+}
 
-	return strings.Join(sentences[:len(sentences)-1], ".") + ".", nil
+// Model returns the name of the model requests are sent to.
+func (b *OpenAIBackend) Model() string {
+	return b.model
 }
@@ -0,0 +1,102 @@
+package summary
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenAIBackendComplete(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    Completion
+		wantErr string
+	}{
+		{
+			name:   "success",
+			status: http.StatusOK,
+			body: `{"model":"gpt-test-2025","choices":[{"index":0,"message":{"role":"assistant","content":"Short summary."}}],
+				"usage":{"prompt_tokens":90,"completion_tokens":10,"total_tokens":100}}`,
+			want: Completion{Text: "Short summary.", Model: "gpt-test-2025", PromptTokens: 90, CompletionTokens: 10},
+		},
+		{
+			name:    "error body",
+			status:  http.StatusTooManyRequests,
+			body:    `{"error":{"message":"Rate limit reached","type":"requests","code":"rate_limit_exceeded"}}`,
+			wantErr: "Rate limit reached",
+		},
+		{
+			name:    "non-200 status",
+			status:  http.StatusBadGateway,
+			body:    `<html>Bad Gateway</html>`,
+			wantErr: "502",
+		},
+		{
+			name:    "no choices",
+			status:  http.StatusOK,
+			body:    `{"model":"gpt-test-2025","choices":[]}`,
+			wantErr: "no choices",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/chat/completions" {
+					t.Errorf("path = %q, want /v1/chat/completions", r.URL.Path)
+				}
+
+				if r.Header.Get("Authorization") != "Bearer key" {
+					t.Errorf("authorization = %q", r.Header.Get("Authorization"))
+				}
+
+				var params struct {
+					Model    string `json:"model"`
+					Messages []struct {
+						Role    string `json:"role"`
+						Content string `json:"content"`
+					} `json:"messages"`
+					MaxTokens int `json:"max_tokens"`
+				}
+
+				if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+					t.Errorf("decode request: %v", err)
+				}
+
+				if params.Model != "gpt-test" || params.MaxTokens != 100 || len(params.Messages) != 2 ||
+					params.Messages[0].Role != "system" || params.Messages[1].Content != "user" {
+					t.Errorf("unexpected request: %+v", params)
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			backend := NewOpenAIBackend("key", srv.URL+"/v1", "gpt-test")
+
+			got, err := backend.Complete(context.Background(), Request{System: "system", User: "user", MaxTokens: 100})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want it to contain %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("completion = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
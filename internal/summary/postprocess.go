@@ -0,0 +1,266 @@
+package summary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/kirinyoku/echo-wire-bot/internal/lang"
+)
+
+// correctivePrompt is appended to the system prompt when a summary is rejected by the post-processing.
+// The {problem} placeholder is replaced with the reason of the rejection.
+const correctivePrompt = "Your previous reply was rejected: {problem}. Follow the instructions above and reply " +
+	"with the summary only, as plain text without Markdown."
+
+// correctiveLanguage is appended to the corrective prompt when the summary is expected in a language,
+// whose name replaces the {language} placeholder.
+const correctiveLanguage = " Write it in {language}."
+
+// errEmptySummary is returned for summaries that are empty after the post-processing.
+var errEmptySummary = errors.New("empty summary in model response")
+
+// errWrongLanguage is returned for summaries that are not written in the expected language.
+var errWrongLanguage = errors.New("summary is not in the expected language")
+
+// markdownRules convert the Markdown formatting of model replies to plain text, in order.
+var markdownRules = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile("(?m)^\\s*```.*$"), ""},
+	{regexp.MustCompile(`(?m)^\s*(?:-{3,}|\*{3,}|_{3,})\s*$`), ""},
+	{regexp.MustCompile(`(?m)^\s{0,3}#{1,6}\s.*$`), ""},
+	{regexp.MustCompile(`(?m)^\s*>\s?`), ""},
+	{regexp.MustCompile(`(?m)^\s*[-*+]\s+`), "• "},
+	{regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`), "$1"},
+	{regexp.MustCompile("`([^`]*)`"), "$1"},
+	{regexp.MustCompile(`\*\*(.+?)\*\*`), "$1"},
+	{regexp.MustCompile(`__(.+?)__`), "$1"},
+	{regexp.MustCompile(`~~(.+?)~~`), "$1"},
+	{regexp.MustCompile(`\*(\S(?:[^*]*\S)?)\*`), "$1"},
+	{regexp.MustCompile(`(^|[^\pL\pN])_(\S(?:[^_]*\S)?)_([^\pL\pN]|$)`), "$1$2$3"},
+	{regexp.MustCompile(`</?[a-zA-Z][^>]*>`), ""},
+}
+
+// listItem matches the marker of a list item at the start of a line.
+var listItem = regexp.MustCompile(`^(?:•|\d+[.)])\s`)
+
+// paragraph is a line of a summary split into sentences.
+type paragraph struct {
+	sentences []string
+	// gap marks paragraphs preceded by a blank line.
+	gap bool
+}
+
+// finish requests the summary of the text with the prompt and post-processes it, adding the usage to the result.
+// The summary is split into sentences by the rules of the language with the code and checked against
+// the expected language, unless it is empty. A summary rejected by the post-processing, e.g. in the wrong
+// language, is requested once more with a corrective prompt.
+func (s *LLMSummarizer) finish(ctx context.Context, text, prompt, code, expected string, result *Result) error {
+	req := Request{
+		System:      prompt,
+		User:        text,
+		MaxTokens:   summaryMaxTokens,
+		Temperature: 1,
+		Purpose:     PurposeSummary,
+	}
+
+	for attempt := 0; ; attempt++ {
+		completion, err := s.complete(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		result.add(completion)
+		result.Model = completion.Model
+		result.Text = s.clean(completion.Text, code)
+
+		err = s.check(result.Text, expected)
+		if err == nil {
+			return nil
+		}
+
+		if attempt == 1 {
+			return err
+		}
+
+		log.Printf("[WARN] summary rejected, retrying with a corrective prompt: %v", err)
+
+		corrective := correctivePrompt
+		if expected != "" {
+			corrective += correctiveLanguage
+		}
+
+		req.System = prompt + "\n\n" + strings.NewReplacer(
+			"{problem}", err.Error(),
+			"{language}", lang.Name(expected),
+		).Replace(corrective)
+	}
+}
+
+// clean converts the summary to plain text, drops its last sentence if it has been cut off
+// and shortens it to the maximum length on a sentence boundary. Sentences are split according
+// to the language with the given ISO 639-1 code, if known.
+func (s *LLMSummarizer) clean(text, code string) string {
+	var (
+		paragraphs []paragraph
+		gap        bool
+	)
+
+	for _, line := range strings.Split(plainText(text), "\n") {
+		sentences := lang.Sentences(line, code)
+		if len(sentences) == 0 {
+			gap = len(paragraphs) > 0
+			continue
+		}
+
+		paragraphs = append(paragraphs, paragraph{sentences: sentences, gap: gap})
+		gap = false
+	}
+
+	paragraphs = dropUnfinished(paragraphs)
+
+	if s.maxLength > 0 {
+		paragraphs = limitLength(paragraphs, s.maxLength)
+	}
+
+	var b strings.Builder
+
+	for i, p := range paragraphs {
+		if i > 0 {
+			b.WriteString("\n")
+
+			if p.gap {
+				b.WriteString("\n")
+			}
+		}
+
+		b.WriteString(lang.Join(p.sentences))
+	}
+
+	return b.String()
+}
+
+// check returns an error if the summary is empty or, when the language check is enabled,
+// is not written in the language with the given ISO 639-1 code. Summaries whose language
+// cannot be detected pass the check.
+func (s *LLMSummarizer) check(text, code string) error {
+	if text == "" {
+		return errEmptySummary
+	}
+
+	if !s.checkLanguage || code == "" {
+		return nil
+	}
+
+	if detected := lang.Detect(text); detected != "" && !lang.Same(detected, code) {
+		return fmt.Errorf("%w: %s instead of %s", errWrongLanguage, lang.Name(detected), lang.Name(code))
+	}
+
+	return nil
+}
+
+// plainText removes the Markdown and HTML formatting from the text, keeping list items as bullets.
+// Headings are removed along with their text, since they title the reply rather than summarize the article.
+func plainText(text string) string {
+	for _, rule := range markdownRules {
+		text = rule.pattern.ReplaceAllString(text, rule.replacement)
+	}
+
+	return text
+}
+
+// dropUnfinished drops the last sentence of the paragraphs if it does not end with a sentence terminator,
+// which means the reply of the model has been cut off. The only sentence and list items are kept,
+// since they may lack a terminator on purpose.
+func dropUnfinished(paragraphs []paragraph) []paragraph {
+	if len(paragraphs) == 0 {
+		return paragraphs
+	}
+
+	last := &paragraphs[len(paragraphs)-1]
+	sentence := last.sentences[len(last.sentences)-1]
+
+	if lang.IsComplete(sentence) || (len(paragraphs) == 1 && len(last.sentences) == 1) {
+		return paragraphs
+	}
+
+	if len(last.sentences) == 1 && listItem.MatchString(sentence) {
+		return paragraphs
+	}
+
+	last.sentences = last.sentences[:len(last.sentences)-1]
+	if len(last.sentences) == 0 {
+		paragraphs = paragraphs[:len(paragraphs)-1]
+	}
+
+	return paragraphs
+}
+
+// limitLength keeps the leading sentences of the paragraphs that fit into maxLength characters.
+// If even the first sentence does not fit, it is cut on a word boundary and ends with an ellipsis.
+func limitLength(paragraphs []paragraph, maxLength int) []paragraph {
+	var (
+		limited []paragraph
+		length  int
+	)
+
+	for i, p := range paragraphs {
+		kept := paragraph{gap: p.gap}
+
+		for j, sentence := range p.sentences {
+			separator := 0
+			if j > 0 {
+				separator = 1
+			} else if i > 0 {
+				separator = 1
+				if p.gap {
+					separator = 2
+				}
+			}
+
+			size := separator + utf8.RuneCountInString(sentence)
+			if length+size > maxLength {
+				if length == 0 {
+					return []paragraph{{sentences: []string{truncate(sentence, maxLength)}}}
+				}
+
+				if len(kept.sentences) > 0 {
+					limited = append(limited, kept)
+				}
+
+				return limited
+			}
+
+			length += size
+			kept.sentences = append(kept.sentences, sentence)
+		}
+
+		limited = append(limited, kept)
+	}
+
+	return limited
+}
+
+// truncate cuts the text to at most maxLength characters on a word boundary, ending it with an ellipsis.
+func truncate(text string, maxLength int) string {
+	runes := []rune(text)
+	if len(runes) <= maxLength {
+		return text
+	}
+
+	cut := string(runes[:max(maxLength-1, 0)])
+	if i := strings.LastIndexFunc(cut, unicode.IsSpace); i > 0 {
+		cut = cut[:i]
+	}
+
+	return strings.TrimRightFunc(cut, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}) + "…"
+}
@@ -0,0 +1,195 @@
+package summary
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"bold and italic", "**Prices** rose by *5%* and __wages__ by _2%_.", "Prices rose by 5% and wages by 2%."},
+		{"snake case is kept", "The snake_case_name stays.", "The snake_case_name stays."},
+		{"heading", "## Summary\nThe vote passed.", "\nThe vote passed."},
+		{"list", "- First point.\n* Second point.", "• First point.\n• Second point."},
+		{"link and image", "See [the report](https://example.com) ![chart](https://example.com/c.png).", "See the report chart."},
+		{"code", "```\nThe `vote` passed.\n```", "\nThe vote passed.\n"},
+		{"quote and rule", "> The vote passed.\n---", "The vote passed.\n"},
+		{"html", "The <b>vote</b> passed.", "The vote passed."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := plainText(tt.text); got != tt.want {
+				t.Errorf("plainText(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDropUnfinished(t *testing.T) {
+	tests := []struct {
+		name       string
+		paragraphs []paragraph
+		want       []paragraph
+	}{
+		{
+			name:       "complete",
+			paragraphs: []paragraph{{sentences: []string{"One.", "Two."}}},
+			want:       []paragraph{{sentences: []string{"One.", "Two."}}},
+		},
+		{
+			name:       "unfinished sentence",
+			paragraphs: []paragraph{{sentences: []string{"One.", "Two is cut"}}},
+			want:       []paragraph{{sentences: []string{"One."}}},
+		},
+		{
+			name: "unfinished paragraph",
+			paragraphs: []paragraph{
+				{sentences: []string{"One."}},
+				{sentences: []string{"Two is cut"}, gap: true},
+			},
+			want: []paragraph{{sentences: []string{"One."}}},
+		},
+		{
+			name:       "only sentence",
+			paragraphs: []paragraph{{sentences: []string{"A headline without a period"}}},
+			want:       []paragraph{{sentences: []string{"A headline without a period"}}},
+		},
+		{
+			name: "list item",
+			paragraphs: []paragraph{
+				{sentences: []string{"• First point"}},
+				{sentences: []string{"• Second point"}},
+			},
+			want: []paragraph{
+				{sentences: []string{"• First point"}},
+				{sentences: []string{"• Second point"}},
+			},
+		},
+		{
+			name:       "closing quote",
+			paragraphs: []paragraph{{sentences: []string{"One.", `He said "it is over."`}}},
+			want:       []paragraph{{sentences: []string{"One.", `He said "it is over."`}}},
+		},
+		{
+			name:       "empty",
+			paragraphs: nil,
+			want:       nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dropUnfinished(tt.paragraphs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("dropUnfinished() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimitLength(t *testing.T) {
+	tests := []struct {
+		name       string
+		paragraphs []paragraph
+		maxLength  int
+		want       []paragraph
+	}{
+		{
+			name:       "fits",
+			paragraphs: []paragraph{{sentences: []string{"One.", "Two."}}},
+			maxLength:  9,
+			want:       []paragraph{{sentences: []string{"One.", "Two."}}},
+		},
+		{
+			name:       "drops the last sentence",
+			paragraphs: []paragraph{{sentences: []string{"One.", "Two."}}},
+			maxLength:  8,
+			want:       []paragraph{{sentences: []string{"One."}}},
+		},
+		{
+			name: "counts paragraph gaps",
+			paragraphs: []paragraph{
+				{sentences: []string{"One."}},
+				{sentences: []string{"Two."}, gap: true},
+			},
+			maxLength: 9,
+			want:      []paragraph{{sentences: []string{"One."}}},
+		},
+		{
+			name: "keeps whole paragraphs",
+			paragraphs: []paragraph{
+				{sentences: []string{"One."}},
+				{sentences: []string{"Two.", "Three."}},
+			},
+			maxLength: 12,
+			want: []paragraph{
+				{sentences: []string{"One."}},
+				{sentences: []string{"Two."}},
+			},
+		},
+		{
+			name:       "counts characters rather than bytes",
+			paragraphs: []paragraph{{sentences: []string{"Ціни зросли.", "Так."}}},
+			maxLength:  17,
+			want:       []paragraph{{sentences: []string{"Ціни зросли.", "Так."}}},
+		},
+		{
+			name:       "cuts a long first sentence",
+			paragraphs: []paragraph{{sentences: []string{"The parliament passed the budget.", "Two."}}},
+			maxLength:  20,
+			want:       []paragraph{{sentences: []string{"The parliament…"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := limitLength(tt.paragraphs, tt.maxLength); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("limitLength() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClean(t *testing.T) {
+	s := &LLMSummarizer{maxLength: 60}
+
+	text := "## Summary\n**The vote** passed on Sunday.\n\nTurnout was 61.5%, a record. Analysts expect"
+	want := "The vote passed on Sunday.\n\nTurnout was 61.5%, a record."
+
+	if got := s.clean(text, "en"); got != want {
+		t.Errorf("clean() = %q, want %q", got, want)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	s := &LLMSummarizer{checkLanguage: true}
+
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+		wantErr  error
+	}{
+		{"empty", "", "en", errEmptySummary},
+		{"no expected language", "Die Abstimmung wurde am Sonntag mit großer Mehrheit angenommen.", "", nil},
+		{
+			name:     "wrong language",
+			text:     "Die Abstimmung wurde am Sonntag mit großer Mehrheit angenommen, und die Wahlbeteiligung war hoch.",
+			expected: "en",
+			wantErr:  errWrongLanguage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.check(tt.text, tt.expected); !errors.Is(err, tt.wantErr) {
+				t.Errorf("check() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package summary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+)
+
+// Purposes of requests to a language model, recorded along with their usage.
+const (
+	PurposeSummary     = "summary"
+	PurposeChunk       = "chunk"
+	PurposeTranslation = "translation"
+	PurposeEntities    = "entities"
+	PurposeUrgency     = "urgency"
+	PurposeEnrichment  = "enrichment"
+)
+
+// spendRefresh is how often the cached spend is read from the storage again,
+// picking up the usage recorded by other instances of the bot.
+const spendRefresh = time.Minute
+
+// ErrBudgetExceeded is returned when the daily or monthly budget of the language model has been spent.
+var ErrBudgetExceeded = errors.New("language model budget exceeded")
+
+// Price is the price of a model in US dollars per million tokens.
+type Price struct {
+	Input  float64
+	Output float64
+}
+
+// ParsePrices parses the prices of models given as "input/output", in US dollars per million tokens,
+// e.g. "0.15/0.60". A single number is used for both input and output tokens.
+func ParsePrices(prices map[string]string) (map[string]Price, error) {
+	parsed := make(map[string]Price, len(prices))
+
+	for model, value := range prices {
+		input, output, found := strings.Cut(value, "/")
+		if !found {
+			output = input
+		}
+
+		inputPrice, err := strconv.ParseFloat(strings.TrimSpace(input), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid input price of model %s: %w", model, err)
+		}
+
+		outputPrice, err := strconv.ParseFloat(strings.TrimSpace(output), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid output price of model %s: %w", model, err)
+		}
+
+		parsed[model] = Price{Input: inputPrice, Output: outputPrice}
+	}
+
+	return parsed, nil
+}
+
+// UsageStorage defines the interface for storing the token usage of requests to a language model.
+type UsageStorage interface {
+	StoreUsage(ctx context.Context, usage models.LLMUsage) error
+	// Spend returns the total cost of the requests made since the given time.
+	Spend(ctx context.Context, since time.Time) (float64, error)
+}
+
+// Accountant records the token usage and the cost of requests to a language model,
+// and stops requests once the daily or monthly budget has been spent.
+type Accountant struct {
+	usage         UsageStorage
+	prices        map[string]Price
+	dailyBudget   float64
+	monthlyBudget float64
+	skip          bool
+
+	mu      sync.Mutex
+	daily   spend
+	monthly spend
+}
+
+// spend is the cached spend of a budget period, so that the storage is not queried before every request.
+type spend struct {
+	since       time.Time
+	amount      float64
+	refreshedAt time.Time
+}
+
+// NewAccountant creates a new Accountant.
+// Parameters:
+// - usage: The storage of the token usage.
+// - prices: The prices of models; models reported with a version suffix use the price of the longest matching prefix.
+// - dailyBudget: The maximum spend per day in US dollars, 0 for no limit.
+// - monthlyBudget: The maximum spend per month in US dollars, 0 for no limit.
+// - skip: Whether summaries are skipped once a budget has been spent, instead of using the fallback.
+// Returns:
+// - An initialized Accountant instance.
+func NewAccountant(usage UsageStorage, prices map[string]Price, dailyBudget, monthlyBudget float64, skip bool) *Accountant {
+	return &Accountant{
+		usage:         usage,
+		prices:        prices,
+		dailyBudget:   dailyBudget,
+		monthlyBudget: monthlyBudget,
+		skip:          skip,
+	}
+}
+
+// Allow returns ErrBudgetExceeded if the daily or the monthly budget has been spent.
+// Days and months start at midnight UTC. The spend is cached and read from the storage
+// again every spendRefresh or when a new period starts.
+func (a *Accountant) Allow(ctx context.Context) error {
+	now := time.Now().UTC()
+
+	checks := []struct {
+		budget float64
+		cache  *spend
+		since  time.Time
+		period string
+	}{
+		{a.dailyBudget, &a.daily, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), "daily"},
+		{a.monthlyBudget, &a.monthly, time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC), "monthly"},
+	}
+
+	for _, check := range checks {
+		if check.budget <= 0 {
+			continue
+		}
+
+		spend, err := a.spent(ctx, check.cache, check.since, now)
+		if err != nil {
+			return err
+		}
+
+		if spend >= check.budget {
+			return fmt.Errorf("%w: spent $%.2f of the %s budget of $%.2f", ErrBudgetExceeded, spend, check.period, check.budget)
+		}
+	}
+
+	return nil
+}
+
+// spent returns the spend since the start of the period, read from the storage if the cache
+// is older than spendRefresh or holds the spend of a previous period.
+func (a *Accountant) spent(ctx context.Context, cache *spend, since, now time.Time) (float64, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if cache.since.Equal(since) && now.Sub(cache.refreshedAt) < spendRefresh {
+		return cache.amount, nil
+	}
+
+	amount, err := a.usage.Spend(ctx, since)
+	if err != nil {
+		return 0, err
+	}
+
+	*cache = spend{since: since, amount: amount, refreshedAt: now}
+
+	return amount, nil
+}
+
+// Record stores the usage and the cost of the completion and adds the cost to the cached spend.
+// The article the request was made for is taken from the context, see WithArticleID.
+// A failure to store the usage is logged.
+func (a *Accountant) Record(ctx context.Context, purpose, model string, completion Completion) {
+	if completion.Model != "" {
+		model = completion.Model
+	}
+
+	price := a.price(model)
+	cost := (float64(completion.PromptTokens)*price.Input + float64(completion.CompletionTokens)*price.Output) / 1e6
+
+	a.mu.Lock()
+	a.daily.amount += cost
+	a.monthly.amount += cost
+	a.mu.Unlock()
+
+	if err := a.usage.StoreUsage(ctx, models.LLMUsage{
+		ArticleID:        ArticleIDFromContext(ctx),
+		Model:            model,
+		Purpose:          purpose,
+		PromptTokens:     completion.PromptTokens,
+		CompletionTokens: completion.CompletionTokens,
+		Cost:             cost,
+	}); err != nil {
+		log.Printf("[WARN] failed to record language model usage: %v", err)
+	}
+}
+
+// price returns the price of the model, or of the longest model name it starts with,
+// e.g. the price of "gpt-4o-mini" for "gpt-4o-mini-2024-07-18". Unknown models are free.
+func (a *Accountant) price(model string) Price {
+	if price, ok := a.prices[model]; ok {
+		return price
+	}
+
+	var (
+		best    Price
+		bestLen int
+	)
+
+	for name, price := range a.prices {
+		if strings.HasPrefix(model, name) && len(name) > bestLen {
+			best, bestLen = price, len(name)
+		}
+	}
+
+	return best
+}
+
+// articleIDKey is the context key of the ID of the article requests are made for.
+type articleIDKey struct{}
+
+// WithArticleID returns a copy of the context carrying the ID of the article requests are made for,
+// so that their usage is recorded for the article.
+func WithArticleID(ctx context.Context, articleID int64) context.Context {
+	return context.WithValue(ctx, articleIDKey{}, articleID)
+}
+
+// ArticleIDFromContext returns the ID of the article carried by the context, or 0 if there is none.
+func ArticleIDFromContext(ctx context.Context) int64 {
+	articleID, _ := ctx.Value(articleIDKey{}).(int64)
+	return articleID
+}
@@ -0,0 +1,74 @@
+package summary
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+)
+
+type fakeUsage struct {
+	spend   float64
+	queries int
+	stored  []models.LLMUsage
+}
+
+func (f *fakeUsage) StoreUsage(_ context.Context, usage models.LLMUsage) error {
+	f.stored = append(f.stored, usage)
+	return nil
+}
+
+func (f *fakeUsage) Spend(context.Context, time.Time) (float64, error) {
+	f.queries++
+	return f.spend, nil
+}
+
+func TestAccountantCachesSpend(t *testing.T) {
+	usage := &fakeUsage{spend: 0.5}
+	accountant := NewAccountant(usage, map[string]Price{"gpt-4o-mini": {Input: 1, Output: 2}}, 1, 10, false)
+
+	for i := 0; i < 3; i++ {
+		if err := accountant.Allow(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if usage.queries != 2 {
+		t.Errorf("queries = %d, want one per budget", usage.queries)
+	}
+
+	// 200000 input and 150000 output tokens cost $0.50, which spends the daily budget.
+	accountant.Record(context.Background(), PurposeSummary, "gpt-4o-mini-2024-07-18",
+		Completion{PromptTokens: 200000, CompletionTokens: 150000})
+
+	if len(usage.stored) != 1 || usage.stored[0].Cost != 0.5 {
+		t.Fatalf("stored usage = %+v, want a single usage costing $0.50", usage.stored)
+	}
+
+	if err := accountant.Allow(context.Background()); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("err = %v, want %v", err, ErrBudgetExceeded)
+	}
+
+	if usage.queries != 2 {
+		t.Errorf("queries = %d, the recorded cost must be added to the cached spend", usage.queries)
+	}
+}
+
+func TestAccountantRefreshesSpend(t *testing.T) {
+	usage := &fakeUsage{}
+	accountant := NewAccountant(usage, nil, 1, 0, false)
+
+	if err := accountant.Allow(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Another instance of the bot spent the budget, which is picked up once the cache is stale.
+	usage.spend = 1
+	accountant.daily.refreshedAt = accountant.daily.refreshedAt.Add(-spendRefresh)
+
+	if err := accountant.Allow(context.Background()); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("err = %v, want %v", err, ErrBudgetExceeded)
+	}
+}
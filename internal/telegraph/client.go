@@ -0,0 +1,121 @@
+// Package telegraph publishes articles as Telegraph pages, which Telegram shows in Instant View.
+package telegraph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DefaultBaseURL is the address of the Telegraph API.
+const DefaultBaseURL = "https://api.telegra.ph"
+
+// Account is a Telegraph account.
+type Account struct {
+	ShortName   string `json:"short_name"`
+	AuthorName  string `json:"author_name"`
+	AccessToken string `json:"access_token"`
+}
+
+// Page is a Telegraph page.
+type Page struct {
+	Path  string `json:"path"`
+	URL   string `json:"url"`
+	Title string `json:"title"`
+}
+
+// Client is a client of the Telegraph API.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a new Client for the Telegraph API at the given base URL,
+// e.g. DefaultBaseURL or the address of a local stand-in.
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: httpClient,
+	}
+}
+
+// CreateAccount creates a new Telegraph account.
+func (c *Client) CreateAccount(ctx context.Context, shortName, authorName string) (Account, error) {
+	const op = "telegraph.Client.CreateAccount"
+
+	var account Account
+
+	if err := c.call(ctx, "createAccount", map[string]any{
+		"short_name":  shortName,
+		"author_name": authorName,
+	}, &account); err != nil {
+		return Account{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return account, nil
+}
+
+// CreatePage creates a new Telegraph page owned by the account of the access token.
+// The author link points readers to the original article.
+func (c *Client) CreatePage(ctx context.Context, accessToken, title, authorName, authorURL string, content []Node) (Page, error) {
+	const op = "telegraph.Client.CreatePage"
+
+	var page Page
+
+	if err := c.call(ctx, "createPage", map[string]any{
+		"access_token": accessToken,
+		"title":        title,
+		"author_name":  authorName,
+		"author_url":   authorURL,
+		"content":      content,
+	}, &page); err != nil {
+		return Page{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return page, nil
+}
+
+// call sends a request to a method of the Telegraph API and decodes its result.
+func (c *Client) call(ctx context.Context, method string, params map[string]any, result any) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var apiResp struct {
+		OK     bool            `json:"ok"`
+		Error  string          `json:"error"`
+		Result json.RawMessage `json:"result"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return fmt.Errorf("unexpected response with status code %d: %w", resp.StatusCode, err)
+	}
+
+	if !apiResp.OK {
+		if apiResp.Error == "" {
+			return errors.New("request failed without an error description")
+		}
+
+		return errors.New(apiResp.Error)
+	}
+
+	return json.Unmarshal(apiResp.Result, result)
+}
@@ -0,0 +1,108 @@
+package telegraph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClientCreatePage(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    Page
+		wantErr string
+	}{
+		{
+			name:   "success",
+			status: http.StatusOK,
+			body:   `{"ok":true,"result":{"path":"Title-01-01","url":"https://telegra.ph/Title-01-01","title":"Title"}}`,
+			want:   Page{Path: "Title-01-01", URL: "https://telegra.ph/Title-01-01", Title: "Title"},
+		},
+		{
+			name:    "error body",
+			status:  http.StatusOK,
+			body:    `{"ok":false,"error":"ACCESS_TOKEN_INVALID"}`,
+			wantErr: "ACCESS_TOKEN_INVALID",
+		},
+		{
+			name:    "error without description",
+			status:  http.StatusOK,
+			body:    `{"ok":false}`,
+			wantErr: "without an error description",
+		},
+		{
+			name:    "non-200 status",
+			status:  http.StatusBadGateway,
+			body:    `<html>Bad Gateway</html>`,
+			wantErr: "status code 502",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var params map[string]any
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/createPage" {
+					t.Errorf("path = %q, want /createPage", r.URL.Path)
+				}
+
+				if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+					t.Errorf("decode request: %v", err)
+				}
+
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			client := NewClient(srv.URL+"/", srv.Client())
+
+			page, err := client.CreatePage(context.Background(), "token", "Title", "Author", "https://example.com/a", []Node{NodeElement{Tag: "p"}})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want it to contain %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if page != tt.want {
+				t.Errorf("page = %+v, want %+v", page, tt.want)
+			}
+
+			if params["access_token"] != "token" || params["author_url"] != "https://example.com/a" {
+				t.Errorf("unexpected request params: %v", params)
+			}
+		})
+	}
+}
+
+func TestClientCreateAccount(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/createAccount" {
+			t.Errorf("path = %q, want /createAccount", r.URL.Path)
+		}
+
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"short_name":"Echo","author_name":"Echo Wire","access_token":"abc"}}`))
+	}))
+	defer srv.Close()
+
+	account, err := NewClient(srv.URL, srv.Client()).CreateAccount(context.Background(), "Echo", "Echo Wire")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if account.AccessToken != "abc" {
+		t.Errorf("access token = %q, want abc", account.AccessToken)
+	}
+}
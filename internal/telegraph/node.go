@@ -0,0 +1,180 @@
+package telegraph
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// maxContentBytes limits the size of the page content, which Telegraph caps at 64 KB.
+const maxContentBytes = 60 * 1024
+
+// Node is a node of the content of a Telegraph page: either a string or a *NodeElement.
+type Node any
+
+// NodeElement is an element of the content of a Telegraph page.
+type NodeElement struct {
+	Tag      string            `json:"tag"`
+	Attrs    map[string]string `json:"attrs,omitempty"`
+	Children []Node            `json:"children,omitempty"`
+}
+
+// allowedTags are the tags supported by Telegraph, mapped from the HTML tags they replace.
+var allowedTags = map[string]string{
+	"a": "a", "aside": "aside", "b": "b", "blockquote": "blockquote", "br": "br", "code": "code",
+	"em": "em", "figcaption": "figcaption", "figure": "figure", "hr": "hr", "i": "i", "img": "img",
+	"li": "li", "ol": "ol", "p": "p", "pre": "pre", "s": "s", "strong": "strong", "u": "u", "ul": "ul",
+	"h1": "h3", "h2": "h3", "h3": "h3", "h4": "h4", "h5": "h4", "h6": "h4",
+	"del": "s", "strike": "s", "ins": "u", "cite": "i", "q": "i",
+}
+
+// droppedTags are the tags removed along with their content.
+var droppedTags = map[string]struct{}{
+	"script": {}, "style": {}, "noscript": {}, "iframe": {}, "object": {}, "embed": {}, "video": {},
+	"audio": {}, "form": {}, "button": {}, "input": {}, "select": {}, "textarea": {}, "svg": {},
+	"template": {}, "head": {},
+}
+
+// inlineTags are the tags whose whitespace-only text children are significant.
+var inlineTags = map[string]struct{}{
+	"p": {}, "a": {}, "b": {}, "strong": {}, "i": {}, "em": {}, "u": {}, "s": {}, "code": {}, "span": {},
+	"li": {}, "blockquote": {}, "figcaption": {}, "h1": {}, "h2": {}, "h3": {}, "h4": {}, "h5": {}, "h6": {},
+	"del": {}, "strike": {}, "ins": {}, "cite": {}, "q": {},
+}
+
+// FromHTML converts the readable HTML content of an article into Telegraph nodes.
+// Tags that Telegraph does not support are unwrapped, scripts and embeds are removed,
+// and links and images are kept only with absolute http(s) URLs. Content over the
+// Telegraph size limit is cut at a top-level node.
+func FromHTML(content string) ([]Node, error) {
+	doc, err := html.Parse(strings.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		nodes []Node
+		size  int
+	)
+
+	for _, node := range convertChildren(doc) {
+		data, err := json.Marshal(node)
+		if err != nil {
+			return nil, err
+		}
+
+		if size += len(data) + 1; size > maxContentBytes {
+			break
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	return nodes, nil
+}
+
+// convertChildren converts the children of the HTML node.
+func convertChildren(n *html.Node) []Node {
+	var nodes []Node
+
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		nodes = append(nodes, convert(child)...)
+	}
+
+	return nodes
+}
+
+// convert converts the HTML node into Telegraph nodes. Unsupported elements are replaced with their children.
+func convert(n *html.Node) []Node {
+	switch n.Type {
+	case html.TextNode:
+		return convertText(n)
+	case html.ElementNode:
+	default:
+		return convertChildren(n)
+	}
+
+	if _, ok := droppedTags[n.Data]; ok {
+		return nil
+	}
+
+	tag, ok := allowedTags[n.Data]
+	if !ok {
+		return convertChildren(n)
+	}
+
+	element := &NodeElement{Tag: tag}
+
+	switch tag {
+	case "img":
+		src, ok := safeURL(attr(n, "src"), "http", "https")
+		if !ok {
+			return nil
+		}
+
+		element.Attrs = map[string]string{"src": src}
+
+		return []Node{element}
+	case "br", "hr":
+		return []Node{element}
+	case "a":
+		if href, ok := safeURL(attr(n, "href"), "http", "https", "mailto"); ok {
+			element.Attrs = map[string]string{"href": href}
+		}
+	}
+
+	element.Children = convertChildren(n)
+
+	if len(element.Children) == 0 {
+		return nil
+	}
+
+	return []Node{element}
+}
+
+// convertText converts the HTML text node. Whitespace between block elements is dropped,
+// while whitespace inside inline content is collapsed into a single space.
+func convertText(n *html.Node) []Node {
+	if strings.TrimSpace(n.Data) != "" {
+		return []Node{n.Data}
+	}
+
+	if n.Data == "" || n.Parent == nil {
+		return nil
+	}
+
+	if _, ok := inlineTags[n.Parent.Data]; !ok {
+		return nil
+	}
+
+	return []Node{" "}
+}
+
+// attr returns the value of the attribute of the HTML node.
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+
+	return ""
+}
+
+// safeURL returns the URL if it is absolute and has one of the schemes.
+func safeURL(rawURL string, schemes ...string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Scheme == "" || (u.Host == "" && u.Scheme != "mailto") {
+		return "", false
+	}
+
+	for _, scheme := range schemes {
+		if strings.EqualFold(u.Scheme, scheme) {
+			return u.String(), true
+		}
+	}
+
+	return "", false
+}
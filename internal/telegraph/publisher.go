@@ -0,0 +1,167 @@
+package telegraph
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+)
+
+const (
+	// maxTitleLength and maxAuthorLength are the limits of the Telegraph API, in characters.
+	maxTitleLength  = 256
+	maxAuthorLength = 128
+	// maxShortNameLength is the limit of the short name of a Telegraph account, in characters.
+	maxShortNameLength = 32
+)
+
+// PageStorage defines the interface for storing the Telegraph pages of articles.
+type PageStorage interface {
+	Page(ctx context.Context, articleID int64) (models.TelegraphPage, error)
+	LastAccessToken(ctx context.Context) (string, error)
+	StorePage(ctx context.Context, page models.TelegraphPage) error
+}
+
+// ContentProvider defines the interface for retrieving the content extracted from article pages.
+type ContentProvider interface {
+	Content(ctx context.Context, articleID int64) (models.ArticleContent, error)
+}
+
+// Publisher publishes the extracted content of articles as Telegraph pages.
+type Publisher struct {
+	client     *Client
+	pages      PageStorage
+	contents   ContentProvider
+	authorName string
+
+	mu          sync.Mutex
+	accessToken string
+}
+
+// NewPublisher creates a new Publisher.
+// Parameters:
+// - client: The client of the Telegraph API.
+// - pages: The storage of the created pages.
+// - contents: The provider of the extracted content of articles.
+// - accessToken: The token of the Telegraph account. If empty, the account of the latest page
+// is reused, or a new account is created.
+// - authorName: The author shown on pages of articles without a byline.
+func NewPublisher(client *Client, pages PageStorage, contents ContentProvider, accessToken, authorName string) *Publisher {
+	return &Publisher{
+		client:      client,
+		pages:       pages,
+		contents:    contents,
+		authorName:  authorName,
+		accessToken: accessToken,
+	}
+}
+
+// Publish publishes the article as a Telegraph page and returns its URL.
+// An article that has already been published is not published again.
+// It returns an empty URL if the article has no extracted content to publish.
+func (p *Publisher) Publish(ctx context.Context, article models.Article) (string, error) {
+	const op = "telegraph.Publisher.Publish"
+
+	page, err := p.pages.Page(ctx, article.ID)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if page.URL != "" {
+		return page.URL, nil
+	}
+
+	content, err := p.contents.Content(ctx, article.ID)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if strings.TrimSpace(content.HTML) == "" {
+		return "", nil
+	}
+
+	nodes, err := FromHTML(content.HTML)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(nodes) == 0 {
+		return "", nil
+	}
+
+	token, err := p.token(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	author := p.authorName
+	switch {
+	case content.Byline != "":
+		author = content.Byline
+	case content.SiteName != "":
+		author = content.SiteName
+	}
+
+	created, err := p.client.CreatePage(
+		ctx,
+		token,
+		limit(article.Title, maxTitleLength),
+		limit(author, maxAuthorLength),
+		article.Link,
+		nodes,
+	)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := p.pages.StorePage(ctx, models.TelegraphPage{
+		ArticleID:   article.ID,
+		AccessToken: token,
+		Path:        created.Path,
+		URL:         created.URL,
+	}); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return created.URL, nil
+}
+
+// token returns the access token of the Telegraph account, creating an account if there is none.
+func (p *Publisher) token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.accessToken != "" {
+		return p.accessToken, nil
+	}
+
+	token, err := p.pages.LastAccessToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if token == "" {
+		account, err := p.client.CreateAccount(ctx, limit(p.authorName, maxShortNameLength), limit(p.authorName, maxAuthorLength))
+		if err != nil {
+			return "", err
+		}
+
+		token = account.AccessToken
+	}
+
+	p.accessToken = token
+
+	return token, nil
+}
+
+// limit cuts the text to the maximum number of characters.
+func limit(text string, maxLength int) string {
+	if utf8.RuneCountInString(text) <= maxLength {
+		return text
+	}
+
+	return string([]rune(text)[:maxLength])
+}
@@ -0,0 +1,122 @@
+package telegraph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kirinyoku/echo-wire-bot/internal/models"
+)
+
+type fakePages struct {
+	pages map[int64]models.TelegraphPage
+	token string
+}
+
+func (f *fakePages) Page(_ context.Context, articleID int64) (models.TelegraphPage, error) {
+	return f.pages[articleID], nil
+}
+
+func (f *fakePages) LastAccessToken(context.Context) (string, error) {
+	return f.token, nil
+}
+
+func (f *fakePages) StorePage(_ context.Context, page models.TelegraphPage) error {
+	f.pages[page.ArticleID] = page
+	return nil
+}
+
+type fakeContents map[int64]models.ArticleContent
+
+func (f fakeContents) Content(_ context.Context, articleID int64) (models.ArticleContent, error) {
+	return f[articleID], nil
+}
+
+func TestPublisherPublish(t *testing.T) {
+	var calls []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.URL.Path)
+
+		var params map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+
+		switch r.URL.Path {
+		case "/createAccount":
+			_, _ = w.Write([]byte(`{"ok":true,"result":{"access_token":"new-token"}}`))
+		case "/createPage":
+			if params["access_token"] != "new-token" {
+				t.Errorf("access token = %v, want new-token", params["access_token"])
+			}
+
+			if params["author_name"] != "Jane Doe" {
+				t.Errorf("author = %v, want the byline", params["author_name"])
+			}
+
+			_, _ = w.Write([]byte(`{"ok":true,"result":{"path":"Page-01","url":"https://telegra.ph/Page-01"}}`))
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	pages := &fakePages{pages: make(map[int64]models.TelegraphPage)}
+	contents := fakeContents{
+		1: {ArticleID: 1, HTML: "<p>Some text.</p>", Byline: "Jane Doe"},
+		2: {ArticleID: 2},
+	}
+
+	publisher := NewPublisher(NewClient(srv.URL, srv.Client()), pages, contents, "", "Echo Wire")
+	article := models.Article{ID: 1, Title: "Title", Link: "https://example.com/a"}
+
+	pageURL, err := publisher.Publish(context.Background(), article)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if pageURL != "https://telegra.ph/Page-01" {
+		t.Errorf("url = %q, want https://telegra.ph/Page-01", pageURL)
+	}
+
+	if stored := pages.pages[1]; stored.URL != pageURL || stored.AccessToken != "new-token" {
+		t.Errorf("stored page = %+v", stored)
+	}
+
+	// A published article is not published again.
+	if pageURL, err := publisher.Publish(context.Background(), article); err != nil || pageURL != "https://telegra.ph/Page-01" {
+		t.Errorf("second publish = %q, %v", pageURL, err)
+	}
+
+	// An article without extracted content has nothing to publish.
+	if pageURL, err := publisher.Publish(context.Background(), models.Article{ID: 2}); err != nil || pageURL != "" {
+		t.Errorf("publish without content = %q, %v", pageURL, err)
+	}
+
+	if len(calls) != 2 || calls[0] != "/createAccount" || calls[1] != "/createPage" {
+		t.Errorf("calls = %v, want createAccount and createPage once", calls)
+	}
+}
+
+func TestPublisherPublishError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"ok":false,"error":"CONTENT_TOO_BIG"}`))
+	}))
+	defer srv.Close()
+
+	pages := &fakePages{pages: make(map[int64]models.TelegraphPage), token: "stored-token"}
+	contents := fakeContents{1: {ArticleID: 1, HTML: "<p>Some text.</p>"}}
+
+	publisher := NewPublisher(NewClient(srv.URL, srv.Client()), pages, contents, "", "Echo Wire")
+
+	if _, err := publisher.Publish(context.Background(), models.Article{ID: 1}); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if len(pages.pages) != 0 {
+		t.Errorf("a failed page must not be stored: %v", pages.pages)
+	}
+}